@@ -0,0 +1,57 @@
+package Context
+
+import (
+	"errors"
+	"log"
+)
+
+// ErrResponseWritten is reported when a response is sent after another one was already written,
+// for example when a handler calls RespondOK after a middleware answered with ErrorUnauthorized.
+var ErrResponseWritten = errors.New("response already written")
+
+// Abort stops the handler chain: the middlewares and the handler that did not run yet are skipped.
+// The middlewares that already ran still regain control once the current handler returns,
+// and can check IsAborted to tell a short-circuited request.
+// Abort does not write anything, so the response must be written before or after calling it:
+//
+//	if !allowed {
+//		c.ErrorForbidden("Access denied")
+//		return
+//	}
+//
+// The Error helpers call Abort themselves.
+func (c *Context) Abort() {
+	c.aborted = true
+}
+
+// AbortWithStatus stops the handler chain, like Abort, and sends the response with the given status code and no body.
+func (c *Context) AbortWithStatus(status int) {
+	c.Abort()
+	c.SetStatus(status)
+	c.Writer.WriteHeaderNow()
+}
+
+// IsAborted reports whether the handler chain was stopped with Abort.
+func (c *Context) IsAborted() bool {
+	return c.aborted
+}
+
+// Written reports whether the status code and headers of the response were already sent to the client,
+// after which the status code can no longer be changed and the Respond and Error helpers do nothing.
+func (c *Context) Written() bool {
+	return c.Writer.Written()
+}
+
+// checkWritable reports whether a new response can be sent for the request.
+// When the response was already written, the new one is ignored, and reported in the logs in development mode
+// so that handlers answering twice are easy to spot.
+func (c *Context) checkWritable(status int) bool {
+	if !c.Written() {
+		return true
+	}
+	if c.DevMode {
+		log.Printf("[ERROR] [%s] %s %s - %v: status %d ignored, %d already sent",
+			c.RequestID(), c.Method, c.Path, ErrResponseWritten, status, c.GetStatus())
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package Context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAbort(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(c *Context)
+		status  int
+		body    string
+		aborted bool
+	}{
+		{"abort without response", func(c *Context) { c.Abort() }, http.StatusOK, "", true},
+		{"abort with status", func(c *Context) { c.AbortWithStatus(http.StatusUnauthorized) }, http.StatusUnauthorized, "", true},
+		{"error helper", func(c *Context) { c.ErrorForbidden("Access denied") }, http.StatusForbidden, `{"error":"Access denied"}` + "\n", true},
+		{"error then respond", func(c *Context) {
+			c.ErrorUnauthorized("Missing token")
+			c.RespondOK("ok")
+		}, http.StatusUnauthorized, `{"error":"Missing token"}` + "\n", true},
+		{"respond twice", func(c *Context) {
+			c.RespondCreated("first")
+			c.RespondOK("second")
+		}, http.StatusCreated, `"first"` + "\n", false},
+		{"status after abort with status", func(c *Context) {
+			c.AbortWithStatus(http.StatusTooManyRequests)
+			c.SetStatus(http.StatusOK)
+			c.ErrorInternalServerError("late")
+		}, http.StatusTooManyRequests, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c := NewContext(w, httptest.NewRequest("GET", "/", nil))
+			c.DevMode = true
+			tt.handler(c)
+			c.Writer.WriteHeaderNow()
+
+			if got := c.GetStatus(); got != tt.status {
+				t.Errorf("expected GetStatus %d, got %d", tt.status, got)
+			}
+			if w.Code != tt.status || w.Body.String() != tt.body {
+				t.Errorf("expected %d %q, got %d %q", tt.status, tt.body, w.Code, w.Body)
+			}
+			if c.IsAborted() != tt.aborted {
+				t.Errorf("expected IsAborted to be %v", tt.aborted)
+			}
+		})
+	}
+}
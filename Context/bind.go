@@ -0,0 +1,383 @@
+package Context
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bindSources lists the struct tags read by Bind, in the order they are looked up on each field.
+var bindSources = []string{"path", "query", "header", "cookie", "form"}
+
+// bindField describes a field of a struct bound by Bind.
+// The index is the path to the field through the embedded and nested structs,
+// the source the tag the field is bound from, such as "query", or "json" for fields decoded from the body,
+// and name the name of the value in that source.
+// The def field holds the value of the default tag, used when the source has no value for the field,
+// and timeFormat the layout of the time_format tag for time.Time fields.
+type bindField struct {
+	index      []int
+	source     string
+	name       string
+	def        string
+	hasDefault bool
+	timeFormat string
+}
+
+// bindCache holds the bindFields of the struct types already bound, keyed by their reflect.Type.
+var bindCache sync.Map
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType     = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// Bind fills the struct pointed to by obj from the request, according to the tags of its fields:
+// "path" for the path parameters, "query" for the query string, "header" for the request headers,
+// "cookie" for the cookies and "form" for the fields of a URL-encoded or multipart form body,
+// including uploaded files for *multipart.FileHeader and []*multipart.FileHeader fields.
+// When the request has a JSON or XML body, it is first decoded into the struct as by BindBody,
+// so fields tagged "json" or "xml" are filled as usual.
+// Values are converted to the type of the field: strings, booleans, integers, floats, time.Time (RFC 3339,
+// or the layout of the time_format tag), time.Duration, types implementing encoding.TextUnmarshaler,
+// pointers to any of them, and slices of them for repeated values.
+// The default tag gives the value used when the request has none for the field, as a comma-separated list for slices.
+// Embedded structs and nested structs without tag are bound recursively.
+// Every field is processed, and the struct is then checked against its validate tags, see Validate.
+// The conversion errors, including every type error of the JSON body, and the validation errors of the other fields
+// are returned together as ValidationErrors, rendered as a 422 Unprocessable Entity response by the DefaultErrorHandler.
+// A malformed body is reported as a 400 Bad Request HTTPError:
+//
+//	type ListUsers struct {
+//		OrgID  int       `path:"org"`
+//		Page   int       `query:"page" default:"1"`
+//		Tags   []string  `query:"tag"`
+//		Since  time.Time `query:"since" time_format:"2006-01-02"`
+//		Token  string    `header:"X-Token"`
+//	}
+//
+//	var req ListUsers
+//	if err := c.Bind(&req); err != nil {
+//		return err
+//	}
+func (c *Context) Bind(obj any) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind: expected a non-nil pointer to a struct, got %T", obj)
+	}
+	v = v.Elem()
+
+	var errs ValidationErrors
+	if mediaType := c.mediaType(); hasBody(c.Request) && (isJSONMediaType(mediaType) || isXMLMediaType(mediaType)) {
+		bodyErrs, err := c.decodeBody(obj, mediaType)
+		if err != nil {
+			return err
+		}
+		errs = append(errs, bodyErrs...)
+	}
+
+	tagErrs, err := c.bindTags(v)
+	if err != nil {
+		return err
+	}
+	errs = append(errs, tagErrs...)
+	return validateBound(obj, errs)
+}
+
+// validateBound checks the bound struct against its validate tags, see Validate, and returns the conversion errors
+// of the binding along with the validation errors of the other fields, so that every invalid field is reported at once.
+// The fields that could not be converted are not validated, since they hold their zero value.
+func validateBound(obj any, errs ValidationErrors) error {
+	err := Validate(obj)
+	if len(errs) == 0 {
+		return err
+	}
+	var validationErrs ValidationErrors
+	if err != nil && !errors.As(err, &validationErrs) {
+		return err
+	}
+	for _, fe := range validationErrs {
+		if !slices.ContainsFunc(errs, func(bound FieldError) bool { return bound.Field == fe.Field }) {
+			errs = append(errs, fe)
+		}
+	}
+	return errs
+}
+
+// bindTags fills the fields of the struct from the sources of their tags, see Bind.
+// When sources are given, only the fields bound from them are filled.
+// It returns the conversion errors of the fields, or an HTTPError if the form body cannot be parsed.
+func (c *Context) bindTags(v reflect.Value, sources ...string) (ValidationErrors, error) {
+	var errs ValidationErrors
+	var query url.Values
+	for _, f := range cachedBindFields(v.Type()) {
+		if len(sources) > 0 && !slices.Contains(sources, f.source) {
+			continue
+		}
+		field := v.FieldByIndex(f.index)
+
+		var values []string
+		switch f.source {
+		case "path":
+			if val, ok := c.Params.Get(f.name); ok {
+				values = []string{val}
+			}
+		case "query":
+			if query == nil {
+				query = c.Request.URL.Query()
+			}
+			values = query[f.name]
+		case "header":
+			values = c.Request.Header.Values(f.name)
+		case "cookie":
+			if cookie, err := c.Request.Cookie(f.name); err == nil {
+				values = []string{cookie.Value}
+			}
+		case "form":
+			if err := c.parseForm(); err != nil {
+				return nil, err
+			}
+			if field.Type() == fileHeaderType || field.Type() == fileHeadersType {
+				setFiles(field, c.Request.MultipartForm, f.name)
+				continue
+			}
+			values = c.Request.PostForm[f.name]
+		case "json":
+			if !f.hasDefault || !field.IsZero() {
+				continue
+			}
+		}
+
+		if len(values) == 0 {
+			if !f.hasDefault {
+				continue
+			}
+			values = []string{f.def}
+			if field.Kind() == reflect.Slice {
+				values = strings.Split(f.def, ",")
+			}
+		}
+
+		if err := setField(field, values, f.timeFormat); err != nil {
+			errs = append(errs, FieldError{Field: f.name, Rule: "type", Message: err.Error()})
+		}
+	}
+	return errs, nil
+}
+
+// setFiles stores the files uploaded under the given name in a *multipart.FileHeader
+// or []*multipart.FileHeader field.
+func setFiles(field reflect.Value, form *multipart.Form, name string) {
+	if form == nil || len(form.File[name]) == 0 {
+		return
+	}
+	if field.Type() == fileHeaderType {
+		field.Set(reflect.ValueOf(form.File[name][0]))
+		return
+	}
+	field.Set(reflect.ValueOf(form.File[name]))
+}
+
+// cachedBindFields returns the bindFields of the struct type, computing them on first use.
+func cachedBindFields(t reflect.Type) []bindField {
+	if fields, ok := bindCache.Load(t); ok {
+		return fields.([]bindField)
+	}
+	fields, _ := bindCache.LoadOrStore(t, bindFields(t, nil))
+	return fields.([]bindField)
+}
+
+// bindFields lists the bound fields of the struct type, whose index is prefixed with the given index.
+// Fields with a "json" tag are only listed when they have a default, since the body is decoded by encoding/json.
+func bindFields(t reflect.Type, index []int) []bindField {
+	var fields []bindField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		idx := append(append([]int(nil), index...), i)
+		if !sf.IsExported() {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				fields = append(fields, bindFields(sf.Type, idx)...)
+			}
+			continue
+		}
+		def, hasDefault := sf.Tag.Lookup("default")
+
+		source, name := "", ""
+		for _, src := range bindSources {
+			if tag, ok := sf.Tag.Lookup(src); ok {
+				source, name = src, tag
+				break
+			}
+		}
+		if name == "-" {
+			continue
+		}
+
+		if source == "" {
+			if tag, ok := sf.Tag.Lookup("json"); ok {
+				name, _, _ = strings.Cut(tag, ",")
+				if name == "-" || !hasDefault {
+					continue
+				}
+				if name == "" {
+					name = sf.Name
+				}
+				source = "json"
+			} else if sf.Type.Kind() == reflect.Struct && !isScalarStruct(sf.Type) {
+				fields = append(fields, bindFields(sf.Type, idx)...)
+				continue
+			} else {
+				continue
+			}
+		}
+
+		fields = append(fields, bindField{
+			index:      idx,
+			source:     source,
+			name:       name,
+			def:        def,
+			hasDefault: hasDefault,
+			timeFormat: sf.Tag.Get("time_format"),
+		})
+	}
+	return fields
+}
+
+// isScalarStruct reports whether the struct type is bound from a single value, such as time.Time,
+// rather than field by field.
+func isScalarStruct(t reflect.Type) bool {
+	return t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// setField converts the values to the type of the field and stores them in it.
+// Slices receive one element per value, and the other types the first value.
+func setField(field reflect.Value, values []string, timeFormat string) error {
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, val := range values {
+			if err := setValue(slice.Index(i), val, timeFormat); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setValue(field, values[0], timeFormat)
+}
+
+// setValue converts the value to the type of the field and stores it in it.
+// It returns an error describing the expected type when the value cannot be converted.
+func setValue(field reflect.Value, val string, timeFormat string) error {
+	if field.Kind() == reflect.Pointer {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setValue(ptr.Elem(), val, timeFormat); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+
+	if field.CanAddr() {
+		if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok && field.Type() != timeType {
+			if err := u.UnmarshalText([]byte(val)); err != nil {
+				return fmt.Errorf("invalid value %q: must be %s", val, typeName(field.Type(), timeFormat))
+			}
+			return nil
+		}
+	}
+
+	var err error
+	switch {
+	case field.Type() == timeType:
+		layout := timeFormat
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		var t time.Time
+		if t, err = time.Parse(layout, val); err == nil {
+			field.Set(reflect.ValueOf(t))
+		}
+	case field.Type() == durationType:
+		var d time.Duration
+		if d, err = time.ParseDuration(val); err == nil {
+			field.SetInt(int64(d))
+		}
+	default:
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(val)
+		case reflect.Slice:
+			if field.Type().Elem().Kind() != reflect.Uint8 {
+				return fmt.Errorf("unsupported type %s", field.Type())
+			}
+			field.SetBytes([]byte(val))
+		case reflect.Bool:
+			var b bool
+			if b, err = strconv.ParseBool(val); err == nil {
+				field.SetBool(b)
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			var n int64
+			if n, err = strconv.ParseInt(val, 10, field.Type().Bits()); err == nil {
+				field.SetInt(n)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			var n uint64
+			if n, err = strconv.ParseUint(val, 10, field.Type().Bits()); err == nil {
+				field.SetUint(n)
+			}
+		case reflect.Float32, reflect.Float64:
+			var n float64
+			if n, err = strconv.ParseFloat(val, field.Type().Bits()); err == nil {
+				field.SetFloat(n)
+			}
+		default:
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid value %q: must be %s", val, typeName(field.Type(), timeFormat))
+	}
+	return nil
+}
+
+// typeName returns the description of the type used in conversion error messages, such as "an integer".
+func typeName(t reflect.Type, timeFormat string) string {
+	for t.Kind() == reflect.Pointer || (t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8) {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		if timeFormat == "" {
+			timeFormat = time.RFC3339
+		}
+		return "a time formatted as " + timeFormat
+	case t == durationType:
+		return "a duration such as 1h30m"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a positive integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	}
+	return "a valid " + t.String()
+}
@@ -0,0 +1,62 @@
+package Context
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type bindItem struct {
+	SKU string `json:"sku" validate:"required"`
+	Qty int    `json:"qty" validate:"gte=1"`
+}
+
+type bindOrder struct {
+	Page     int        `query:"page"`
+	Customer string     `json:"customer" validate:"required"`
+	Total    float64    `json:"total" validate:"gt=0"`
+	Count    int        `json:"count" validate:"gte=1"`
+	Items    []bindItem `json:"items" validate:"dive"`
+	Note     string     `json:"note" validate:"max=3"`
+}
+
+func TestBindAggregatesErrors(t *testing.T) {
+	body := `{"total": "ten", "count": "two", "items": [{"sku": "a", "qty": "x"}, {"sku": "", "qty": 2}], "note": "too long"}`
+	req := httptest.NewRequest("POST", "/orders?page=first", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	c := NewContext(httptest.NewRecorder(), req)
+
+	var order bindOrder
+	err := c.Bind(&order)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+
+	var got []string
+	for _, fe := range errs {
+		got = append(got, fe.Field+":"+fe.Rule)
+	}
+	slices.Sort(got)
+	want := []string{"count:type", "customer:required", "items[0].qty:type", "items[1].sku:required", "note:max", "page:type", "total:type"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestBindBodyAggregatesErrors(t *testing.T) {
+	req := httptest.NewRequest("POST", "/orders", strings.NewReader(`{"total": true, "count": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	c := NewContext(httptest.NewRecorder(), req)
+
+	var order bindOrder
+	var errs ValidationErrors
+	if err := c.BindBody(&order); !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	if len(errs) != 2 || errs[0].Field != "total" || errs[0].Rule != "type" || errs[1].Field != "customer" {
+		t.Errorf("expected the type error of total and the missing customer, got %v", errs)
+	}
+}
@@ -0,0 +1,378 @@
+package Context
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the maximum size of the request bodies read by Bind and BindBody
+// when BodyConfig.MaxBytes is zero.
+const DefaultMaxBodyBytes = 10 << 20
+
+// BodyConfig holds the settings applied when reading request bodies with Bind and BindBody.
+// MaxBytes is the maximum size of a body, DefaultMaxBodyBytes if zero and unlimited if negative:
+// larger bodies are rejected with a 413 Request Entity Too Large error.
+// DisallowUnknownFields rejects the JSON bodies containing fields that do not match any field of the struct.
+type BodyConfig struct {
+	MaxBytes              int64
+	DisallowUnknownFields bool
+}
+
+// BindBody decodes the body of the request into the struct pointed to by obj, according to its Content-Type:
+// JSON for application/json and the "+json" media types, XML for application/xml, text/xml and the "+xml" media types,
+// and the "form" tags of the struct for URL-encoded and multipart forms, as with Bind.
+// The struct is then checked against its validate tags, see Validate, and the decoding errors of the fields
+// are reported together with the validation errors of the other fields.
+// The body is limited to BodyConfig.MaxBytes, and larger bodies are rejected with a 413 Request Entity Too Large error.
+// JSON and XML bodies must hold a single document: trailing data is rejected, syntax errors are reported
+// with their line and column, and unknown JSON fields are rejected when BodyConfig.DisallowUnknownFields is set.
+// Other content types are rejected with a 415 Unsupported Media Type error, and an empty body leaves the struct untouched.
+// Malformed bodies are reported as HTTPError, and invalid fields as ValidationErrors,
+// both rendered by the DefaultErrorHandler:
+//
+//	var req CreateUser
+//	if err := c.BindBody(&req); err != nil {
+//		return err
+//	}
+func (c *Context) BindBody(obj any) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind: expected a non-nil pointer to a struct, got %T", obj)
+	}
+	v = v.Elem()
+
+	var errs ValidationErrors
+	var err error
+	mediaType := c.mediaType()
+	switch {
+	case !hasBody(c.Request):
+		errs, err = c.bindTags(v, "json")
+	case isJSONMediaType(mediaType), isXMLMediaType(mediaType):
+		if errs, err = c.decodeBody(obj, mediaType); err == nil {
+			var defaultErrs ValidationErrors
+			defaultErrs, err = c.bindTags(v, "json")
+			errs = append(errs, defaultErrs...)
+		}
+	case mediaType == "application/x-www-form-urlencoded", mediaType == "multipart/form-data":
+		errs, err = c.bindTags(v, "form")
+	default:
+		return ErrUnsupportedMediaType.WithMessage(fmt.Sprintf("Unsupported content type %q", mediaType))
+	}
+
+	if err != nil {
+		return err
+	}
+	return validateBound(obj, errs)
+}
+
+// mediaType returns the media type of the request body, without its parameters, such as "application/json".
+func (c *Context) mediaType() string {
+	mediaType, _, err := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// hasBody reports whether the request has a body.
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody
+}
+
+// isJSONMediaType reports whether the media type is application/json or a JSON structured syntax suffix,
+// such as application/merge-patch+json.
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// isXMLMediaType reports whether the media type is application/xml, text/xml or an XML structured syntax suffix,
+// such as application/atom+xml.
+func isXMLMediaType(mediaType string) bool {
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// maxBodyBytes returns the maximum size of the request body, or a negative value if it is unlimited.
+func (c *Context) maxBodyBytes() int64 {
+	if c.BodyConfig.MaxBytes == 0 {
+		return DefaultMaxBodyBytes
+	}
+	return c.BodyConfig.MaxBytes
+}
+
+// limitBody restricts the request body to the maximum body size.
+// It returns a 413 Request Entity Too Large error when the declared length of the body already exceeds it.
+func (c *Context) limitBody() error {
+	limit := c.maxBodyBytes()
+	if limit < 0 {
+		return nil
+	}
+	if c.Request.ContentLength > limit {
+		return c.bodyTooLarge(limit)
+	}
+	c.Request.Body = http.MaxBytesReader(c.unwrappedWriter(), c.Request.Body, limit)
+	return nil
+}
+
+// unwrappedWriter returns the http.ResponseWriter of the server, below the writer of the Context
+// and the writers of the middlewares, so that http.MaxBytesReader can tell the server to close the connection
+// once a body exceeds the limit.
+func (c *Context) unwrappedWriter() http.ResponseWriter {
+	var w http.ResponseWriter = c.Writer
+	for {
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return w
+		}
+		w = u.Unwrap()
+	}
+}
+
+// bodyTooLarge returns the error reported for bodies exceeding the limit.
+func (c *Context) bodyTooLarge(limit int64) error {
+	return ErrRequestEntityTooLarge.WithMessage(fmt.Sprintf("Request body must not exceed %d bytes", limit))
+}
+
+// readBody reads the whole request body, within the maximum body size.
+// The body is then replaced with a reader over the read bytes, so that it can be read again.
+func (c *Context) readBody() ([]byte, error) {
+	if err := c.limitBody(); err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, c.bodyTooLarge(maxErr.Limit)
+		}
+		return nil, ErrBadRequest.WithMessage("Cannot read request body").Wrap(err)
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// parseForm parses the URL-encoded or multipart form body of the request within the maximum body size,
+// if not already parsed.
+func (c *Context) parseForm() error {
+	if c.Request.PostForm != nil {
+		return nil
+	}
+	if hasBody(c.Request) {
+		if err := c.limitBody(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if c.mediaType() == "multipart/form-data" {
+		err = c.Request.ParseMultipartForm(32 << 20)
+	} else {
+		err = c.Request.ParseForm()
+	}
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return c.bodyTooLarge(maxErr.Limit)
+		}
+		return ErrBadRequest.WithMessage("Invalid form body").Wrap(err)
+	}
+	return nil
+}
+
+// decodeBody reads the JSON or XML body of the request and decodes it into obj.
+// It returns the type errors of the fields, and the unknown JSON fields when they are disallowed, as field errors,
+// or an HTTPError if the body is too large or malformed.
+func (c *Context) decodeBody(obj any, mediaType string) (ValidationErrors, error) {
+	body, err := c.readBody()
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+	if isXMLMediaType(mediaType) {
+		return nil, decodeXML(body, obj)
+	}
+	return decodeJSON(body, obj, c.BodyConfig.DisallowUnknownFields)
+}
+
+// decodeJSON decodes the single JSON document of the body into obj, rejecting trailing data.
+// The values that do not fit their type, and the unknown fields when they are disallowed,
+// are all reported as field errors, by walking the document once it is decoded.
+func decodeJSON(body []byte, obj any, disallowUnknownFields bool) (ValidationErrors, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+
+	var typeErr *json.UnmarshalTypeError
+	if err := dec.Decode(obj); err != nil && !errors.As(err, &typeErr) {
+		var syntaxErr *json.SyntaxError
+		switch {
+		case errors.As(err, &syntaxErr):
+			line, column := textPosition(body, syntaxErr.Offset)
+			return nil, ErrBadRequest.WithMessage(fmt.Sprintf("Invalid JSON body at line %d, column %d: %s", line, column, syntaxErr)).Wrap(err)
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			line, column := textPosition(body, int64(len(body)))
+			return nil, ErrBadRequest.WithMessage(fmt.Sprintf("Invalid JSON body at line %d, column %d: unexpected end of input", line, column)).Wrap(err)
+		default:
+			return nil, ErrBadRequest.WithMessage("Invalid JSON body").Wrap(err)
+		}
+	}
+
+	end := dec.InputOffset()
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		line, column := textPosition(body, nextDataOffset(body, end))
+		return nil, ErrBadRequest.WithMessage(fmt.Sprintf("Invalid JSON body at line %d, column %d: unexpected data after the JSON document", line, column))
+	}
+
+	if typeErr == nil && !disallowUnknownFields {
+		return nil, nil
+	}
+	errs := jsonFieldErrors(body[:end], reflect.TypeOf(obj), "", disallowUnknownFields)
+	if len(errs) == 0 && typeErr != nil {
+		errs = ValidationErrors{{Field: typeErr.Field, Rule: "type", Message: "must be " + typeName(typeErr.Type, "")}}
+	}
+	return errs, nil
+}
+
+// jsonFits reports whether the JSON value can be decoded into a value of type t without error,
+// and without unknown fields when they are disallowed.
+func jsonFits(data []byte, t reflect.Type, disallowUnknownFields bool) bool {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	return dec.Decode(reflect.New(t).Interface()) == nil
+}
+
+// jsonFieldErrors returns a field error for every JSON value of the body that does not fit the type it is decoded into,
+// and for every unknown field of its objects when they are disallowed, since encoding/json only reports the first error.
+// Objects, arrays and maps are walked down to the mismatched values and unknown fields,
+// whose paths are written as by Validate, such as "items[0].qty".
+func jsonFieldErrors(data []byte, t reflect.Type, path string, disallowUnknownFields bool) ValidationErrors {
+	if jsonFits(data, t, disallowUnknownFields) {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var errs ValidationErrors
+	data = bytes.TrimSpace(data)
+	switch {
+	case t.Kind() == reflect.Struct && !isScalarStruct(t) && bytes.HasPrefix(data, []byte("{")):
+		var members map[string]json.RawMessage
+		if json.Unmarshal(data, &members) != nil {
+			break
+		}
+		fields := reflect.VisibleFields(t)
+	members:
+		for key, raw := range members {
+			// encoding/json matches the keys of the object with the names of the fields case-insensitively.
+			for _, sf := range fields {
+				if name, ok := jsonFieldName(sf); ok && strings.EqualFold(name, key) {
+					errs = append(errs, jsonFieldErrors(raw, sf.Type, joinFieldPath(path, name), disallowUnknownFields)...)
+					continue members
+				}
+			}
+			if disallowUnknownFields {
+				errs = append(errs, FieldError{Field: joinFieldPath(path, key), Rule: "unknown", Message: "is not allowed"})
+			}
+		}
+		slices.SortFunc(errs, func(a, b FieldError) int { return strings.Compare(a.Field, b.Field) })
+		return errs
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && bytes.HasPrefix(data, []byte("[")):
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			break
+		}
+		for i, raw := range items {
+			errs = append(errs, jsonFieldErrors(raw, t.Elem(), fmt.Sprintf("%s[%d]", path, i), disallowUnknownFields)...)
+		}
+		return errs
+	case t.Kind() == reflect.Map && bytes.HasPrefix(data, []byte("{")):
+		var members map[string]json.RawMessage
+		if json.Unmarshal(data, &members) != nil {
+			break
+		}
+		for key, raw := range members {
+			errs = append(errs, jsonFieldErrors(raw, t.Elem(), fmt.Sprintf("%s[%s]", path, key), disallowUnknownFields)...)
+		}
+		slices.SortFunc(errs, func(a, b FieldError) int { return strings.Compare(a.Field, b.Field) })
+		return errs
+	}
+	return ValidationErrors{{Field: path, Rule: "type", Message: "must be " + typeName(t, "")}}
+}
+
+// jsonFieldName returns the name of the struct field in JSON documents,
+// and reports false if the field is not decoded by encoding/json, such as an embedded struct without tag,
+// whose fields are promoted.
+func jsonFieldName(sf reflect.StructField) (string, bool) {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if !sf.IsExported() || name == "-" || (sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct) {
+		return "", false
+	}
+	if name == "" {
+		name = sf.Name
+	}
+	return name, true
+}
+
+// decodeXML decodes the single XML document of the body into obj, rejecting trailing elements and text.
+func decodeXML(body []byte, obj any) error {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	if err := dec.Decode(obj); err != nil {
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return ErrBadRequest.WithMessage(fmt.Sprintf("Invalid XML body at line %d: %s", syntaxErr.Line, syntaxErr.Msg)).Wrap(err)
+		}
+		return ErrBadRequest.WithMessage("Invalid XML body: " + err.Error()).Wrap(err)
+	}
+
+	for {
+		end := dec.InputOffset()
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return ErrBadRequest.WithMessage("Invalid XML body: " + err.Error()).Wrap(err)
+		}
+		switch t := tok.(type) {
+		case xml.Comment, xml.ProcInst:
+			continue
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+		}
+		line, column := textPosition(body, nextDataOffset(body, end))
+		return ErrBadRequest.WithMessage(fmt.Sprintf("Invalid XML body at line %d, column %d: unexpected data after the XML document", line, column))
+	}
+}
+
+// nextDataOffset returns the offset of the text up to and including its first non-space byte after the given offset.
+func nextDataOffset(text []byte, offset int64) int64 {
+	for offset < int64(len(text)) && strings.IndexByte(" \t\r\n", text[offset]) >= 0 {
+		offset++
+	}
+	return offset + 1
+}
+
+// textPosition returns the 1-based line and column of the byte at the given offset of the text,
+// where the offset is the number of bytes read up to and including it.
+func textPosition(text []byte, offset int64) (line, column int) {
+	if offset > int64(len(text)) {
+		offset = int64(len(text))
+	}
+	before := text[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - (bytes.LastIndexByte(before, '\n') + 1)
+	return line, column
+}
@@ -0,0 +1,109 @@
+package Context
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBindJSON(t *testing.T) {
+	tests := []struct {
+		body   string
+		config BodyConfig
+		status int
+		fields bool
+	}{
+		{`{"name": "a"}`, BodyConfig{}, 0, false},
+		{`{"name": "a"} {"name": "b"}`, BodyConfig{}, http.StatusBadRequest, false},
+		{`{"name": "a"`, BodyConfig{}, http.StatusBadRequest, false},
+		{`{"name": "` + strings.Repeat("a", 64) + `"}`, BodyConfig{MaxBytes: 32}, http.StatusRequestEntityTooLarge, false},
+		{`{"name": "a", "admin": true}`, BodyConfig{DisallowUnknownFields: true}, 0, true},
+		{`{"extra": 1} garbage`, BodyConfig{DisallowUnknownFields: true}, http.StatusBadRequest, false},
+		{`{"name": "a"} garbage`, BodyConfig{DisallowUnknownFields: true}, http.StatusBadRequest, false},
+		{`{"name": 1}`, BodyConfig{}, 0, true},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+		req.ContentLength = -1
+		c := NewContext(httptest.NewRecorder(), req)
+		c.BodyConfig = tt.config
+
+		var obj struct {
+			Name string `json:"name"`
+		}
+		err := c.BindJSON(&obj)
+
+		var httpErr *HTTPError
+		var errs ValidationErrors
+		switch {
+		case tt.status != 0:
+			if !errors.As(err, &httpErr) || httpErr.Status != tt.status {
+				t.Errorf("%s: expected a %d error, got %v", tt.body, tt.status, err)
+			}
+		case tt.fields:
+			if !errors.As(err, &errs) {
+				t.Errorf("%s: expected field errors, got %v", tt.body, err)
+			}
+		case err != nil || obj.Name != "a":
+			t.Errorf("%s: expected the body to be decoded, got %v and %+v", tt.body, err, obj)
+		}
+	}
+}
+
+func TestDecodeJSONReportsUnknownAndTypeErrors(t *testing.T) {
+	body := `{"extra": 1, "Customer": "a", "total": "ten", "items": [{"sku": "a", "qty": "x", "color": "red"}], "count": "two"}`
+	var order bindOrder
+	errs, err := decodeJSON([]byte(body), &order, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, fe := range errs {
+		got = append(got, fe.Field+":"+fe.Rule)
+	}
+	slices.Sort(got)
+	want := []string{"count:type", "extra:unknown", "items[0].color:unknown", "items[0].qty:type", "total:type"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if order.Customer != "a" {
+		t.Errorf("expected the valid fields to be decoded, got %+v", order)
+	}
+
+	// Unknown fields are accepted unless they are disallowed.
+	if errs, err := decodeJSON([]byte(`{"extra": 1, "customer": "a"}`), &order, false); err != nil || errs != nil {
+		t.Errorf("expected unknown fields to be ignored, got %v and %v", errs, err)
+	}
+}
+
+func TestBodyTooLargeClosesConnection(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := NewContext(NewResponseWriter(w), r)
+		c.BodyConfig = BodyConfig{MaxBytes: 16}
+		var obj map[string]any
+		if err := c.BindJSON(&obj); err != nil {
+			DefaultErrorHandler(c, err)
+		}
+		c.Writer.WriteHeaderNow()
+	}))
+	defer srv.Close()
+
+	// The body is sent without Content-Length, so that the limit is only hit while reading it.
+	body := io.MultiReader(strings.NewReader(`{"data": "`), strings.NewReader(strings.Repeat("a", 1<<20)), strings.NewReader(`"}`))
+	resp, err := http.Post(srv.URL, "application/json", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected a 413 response, got %d", resp.StatusCode)
+	}
+	if !resp.Close {
+		t.Error("expected the server to close the connection after an oversized body")
+	}
+}
@@ -1,21 +1,28 @@
 package Context
 
-import "net/http"
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"maps"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // NewContext creates and returns a new Context instance.
 // It initializes the Context with the provided http.ResponseWriter and http.Request,
-// sets up empty maps for Params and Data, and assigns the request's URL path and method.
+// and assigns the request's URL path and method.
 // This function is typically used to encapsulate HTTP request and response data
 // for further processing within the application.
+// Parameters stored in the request by a parent router, see WithRequestParams, are copied into Params.
+// The Context is not pooled; use AcquireContext and ReleaseContext to reuse contexts across requests.
 func NewContext(w http.ResponseWriter, r *http.Request) *Context {
-	return &Context{
-		Writer:  w,
-		Request: r,
-		Params:  make(map[string]string),
-		Path:    r.URL.Path,
-		Method:  r.Method,
-		Data:    make(map[string]any),
-	}
+	c := new(Context)
+	c.reset(w, r)
+	return c
 }
 
 // Get retrieves the value associated with the given key from the Context's data map.
@@ -27,21 +34,26 @@ func (c *Context) Get(key string) (any, bool) {
 
 // Set stores a key-value pair in the Context's data map.
 // The key is a string, and the value can be of any type.
+// The data map is allocated on first use.
 func (c *Context) Set(key string, value any) {
+	if c.Data == nil {
+		c.Data = make(map[string]any)
+	}
 	c.Data[key] = value
 }
 
-// GetStatus retrieves the HTTP status code from the Context.
+// GetStatus retrieves the HTTP status code of the response from the Context's Writer.
 // If the status is not set, it returns 200 (OK) as the default status code.
-// It also sets the "status" key in the Data map to the retrieved status code.
+// It reflects the status actually set by the handler, whether through SetStatus, a Respond or Error helper,
+// or a direct call to WriteHeader.
 func (c *Context) GetStatus() int {
-	return c.Status
+	return c.Writer.Status()
 }
 
-// SetStatus sets the HTTP status code in the Context.
-// It updates the "status" key in the Data map with the provided status code.
+// SetStatus sets the HTTP status code of the response.
+// The status code is sent with the first write to the body, so it can still be changed until then.
 func (c *Context) SetStatus(status int) {
-	c.Data["status"] = status
+	c.Writer.WriteHeader(status)
 }
 
 // GetMethod retrieves the HTTP method (e.g., GET, POST) from the Context.
@@ -57,11 +69,167 @@ func (c *Context) GetPath() string {
 	return c.Path
 }
 
-// Param retrieves the value of a specific parameter from the Context's Params map.
+// Param retrieves the value of a specific parameter from the Context's Params.
 // It takes a key as an argument and returns the corresponding value as a string.
-// If the key does not exist in the Params map, it returns an empty string.
+// If the key does not exist in the Params, it returns an empty string.
 func (c *Context) Param(key string) string {
-	return c.Params[key]
+	v, _ := c.Params.Get(key)
+	return v
+}
+
+// ParamInt retrieves the value of a specific parameter from the Context's Params as an int.
+// It returns an error if the parameter is missing or is not a valid integer.
+// When the route declares the parameter with the "<int>" constraint, such as "/users/:id<int>",
+// the router only matches valid integers and the conversion cannot fail.
+func (c *Context) ParamInt(key string) (int, error) {
+	return strconv.Atoi(c.Param(key))
+}
+
+// ParamUint retrieves the value of a specific parameter from the Context's Params as an unsigned integer.
+// It returns an error if the parameter is missing or is not a valid unsigned integer.
+// When the route declares the parameter with the "<uint>" constraint, the conversion cannot fail.
+func (c *Context) ParamUint(key string) (uint, error) {
+	v, err := strconv.ParseUint(c.Param(key), 10, 0)
+	return uint(v), err
+}
+
+// ParamFloat retrieves the value of a specific parameter from the Context's Params as a float64.
+// It returns an error if the parameter is missing or is not a valid floating point number.
+// When the route declares the parameter with the "<float>" constraint, the conversion cannot fail.
+func (c *Context) ParamFloat(key string) (float64, error) {
+	return strconv.ParseFloat(c.Param(key), 64)
+}
+
+// ParamBool retrieves the value of a specific parameter from the Context's Params as a bool.
+// It accepts the values understood by strconv.ParseBool, such as "true", "false", "1" or "0".
+// When the route declares the parameter with the "<bool>" constraint, the conversion cannot fail.
+func (c *Context) ParamBool(key string) (bool, error) {
+	return strconv.ParseBool(c.Param(key))
+}
+
+// ParamUUID retrieves the value of a specific parameter from the Context's Params as a UUID.
+// It returns an error if the parameter is missing or is not a valid UUID.
+// When the route declares the parameter with the "<uuid>" constraint, such as "/posts/:slug<uuid>",
+// the router only matches valid UUIDs and the conversion cannot fail.
+func (c *Context) ParamUUID(key string) (uuid.UUID, error) {
+	return uuid.Parse(c.Param(key))
+}
+
+// URL builds the URL path of a named route with the given parameters.
+// It delegates to the router serving the request and returns an error if the route does not exist,
+// if a parameter is missing, or if the Context was not created by a router.
+func (c *Context) URL(name string, params map[string]string) (string, error) {
+	if c.Router == nil {
+		return "", errors.New("no router available to build the URL")
+	}
+	return c.Router.URL(name, params)
+}
+
+// Deadline returns the time when work done on behalf of the request should be canceled.
+// It implements context.Context by delegating to the context of the underlying request.
+func (c *Context) Deadline() (time.Time, bool) {
+	return c.requestContext().Deadline()
+}
+
+// Done returns a channel that is closed when the request is canceled,
+// typically when the client disconnects or the server is shut down.
+// It implements context.Context by delegating to the context of the underlying request.
+func (c *Context) Done() <-chan struct{} {
+	return c.requestContext().Done()
+}
+
+// Err returns a non-nil error once Done is closed, explaining why the request was canceled.
+// It implements context.Context by delegating to the context of the underlying request.
+func (c *Context) Err() error {
+	return c.requestContext().Err()
+}
+
+// Value returns the value associated with the key.
+// String keys are first looked up in the Context's data map, so that values stored with Set are visible,
+// then the lookup falls back to the context of the underlying request.
+// It implements context.Context, which allows passing the Context directly to functions expecting a context.Context
+// while the request is served. Functions keeping the context after the handler returns, or using it from
+// another goroutine, must be given a context made with WithCancel, WithTimeout or WithDeadline instead,
+// since the Context is reused for another request once released.
+func (c *Context) Value(key any) any {
+	if k, ok := key.(string); ok {
+		if val, ok := c.Data[k]; ok {
+			return val
+		}
+	}
+	return c.requestContext().Value(key)
+}
+
+// requestContext returns the context of the underlying request,
+// or context.Background if the Context has no request, for example once released.
+func (c *Context) requestContext() context.Context {
+	if c.Request == nil {
+		return context.Background()
+	}
+	return c.Request.Context()
+}
+
+// detached returns a context.Context carrying the context of the underlying request
+// and a snapshot of the values stored with Set, which does not depend on the Context,
+// so that it stays valid once the Context is released and reused for another request.
+func (c *Context) detached() context.Context {
+	return dataContext{Context: c.requestContext(), data: maps.Clone(c.Data)}
+}
+
+// dataContext is a context.Context whose string keys are looked up in a snapshot of the data map of a Context
+// before its parent, the context of the request.
+type dataContext struct {
+	context.Context
+	data map[string]any
+}
+
+// Value returns the value stored with Set for string keys, or the value of the parent context.
+func (d dataContext) Value(key any) any {
+	if k, ok := key.(string); ok {
+		if val, ok := d.data[k]; ok {
+			return val
+		}
+	}
+	return d.Context.Value(key)
+}
+
+// WithCancel returns a child context.Context of the Context and a function to cancel it.
+// The child is canceled when the cancel function is called or when the request is canceled,
+// for example when the client disconnects, and it carries the values stored with Set before the call.
+// It is derived from the context of the request rather than from the Context itself,
+// so that it can be kept after the handler returns and used from other goroutines, such as by database drivers.
+// It is typically used to bound database calls or outgoing requests to the lifetime of the request.
+func (c *Context) WithCancel() (context.Context, context.CancelFunc) {
+	return context.WithCancel(c.detached())
+}
+
+// WithTimeout returns a child context.Context of the Context that is canceled after the timeout,
+// when the request is canceled, or when the returned cancel function is called.
+// Like the one of WithCancel, the child carries the values stored with Set and stays valid once the Context is released.
+// The cancel function should always be called to release the associated resources:
+//
+//	ctx, cancel := c.WithTimeout(2 * time.Second)
+//	defer cancel()
+//	rows, err := db.QueryContext(ctx, query)
+func (c *Context) WithTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(c.detached(), timeout)
+}
+
+// WithDeadline returns a child context.Context of the Context that is canceled at the deadline,
+// when the request is canceled, or when the returned cancel function is called.
+// Like the one of WithCancel, the child carries the values stored with Set and stays valid once the Context is released.
+func (c *Context) WithDeadline(deadline time.Time) (context.Context, context.CancelFunc) {
+	return context.WithDeadline(c.detached(), deadline)
+}
+
+// PeerCertificate returns the verified certificate of the client, when the request was received over mutual TLS.
+// It returns nil if the connection is not using TLS or if the client did not present a verified certificate.
+// The certificate can be used to identify the calling service, for example through its subject or SANs.
+func (c *Context) PeerCertificate() *x509.Certificate {
+	if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 || len(c.Request.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return c.Request.TLS.VerifiedChains[0][0]
 }
 
 // RequestID retrieves the "request_id" value from the context as a string.
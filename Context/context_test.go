@@ -0,0 +1,91 @@
+package Context
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// ctxKey is the type of the keys stored in the request contexts by the tests.
+type ctxKey string
+
+func TestDerivedContextAfterRelease(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ctxKey("trace"), "abc"))
+	reqCtx, cancelReq := context.WithCancel(req.Context())
+	req = req.WithContext(reqCtx)
+
+	c := AcquireContext(httptest.NewRecorder(), req)
+	c.Set("user", "alice")
+	derived := map[string]context.Context{}
+	var cancels []context.CancelFunc
+	for name, derive := range map[string]func() (context.Context, context.CancelFunc){
+		"WithCancel":   c.WithCancel,
+		"WithTimeout":  func() (context.Context, context.CancelFunc) { return c.WithTimeout(time.Hour) },
+		"WithDeadline": func() (context.Context, context.CancelFunc) { return c.WithDeadline(time.Now().Add(time.Hour)) },
+	} {
+		ctx, cancel := derive()
+		derived[name] = ctx
+		cancels = append(cancels, cancel)
+	}
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	ReleaseContext(c)
+	other := AcquireContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/other", nil))
+	other.Set("user", "bob")
+	defer ReleaseContext(other)
+
+	for name, ctx := range derived {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if got := ctx.Value("user"); got != "alice" {
+				t.Errorf("%s: expected the value stored with Set, got %v", name, got)
+			}
+			if got := ctx.Value(ctxKey("trace")); got != "abc" {
+				t.Errorf("%s: expected the value of the request context, got %v", name, got)
+			}
+			if _, ok := ctx.Deadline(); ok != (name != "WithCancel") {
+				t.Errorf("%s: unexpected deadline presence %v", name, ok)
+			}
+			if err := ctx.Err(); err != nil {
+				t.Errorf("%s: expected a live context, got %v", name, err)
+			}
+		}()
+		<-done
+	}
+
+	cancelReq()
+	for name, ctx := range derived {
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Errorf("%s: not canceled with the request", name)
+		}
+	}
+}
+
+func TestReleasedContextIsSafe(t *testing.T) {
+	c := AcquireContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ReleaseContext(c)
+
+	if v := c.Value("user"); v != nil {
+		t.Errorf("expected no value, got %v", v)
+	}
+	if _, ok := c.Deadline(); ok {
+		t.Error("expected no deadline")
+	}
+	if c.Err() != nil {
+		t.Errorf("expected no error, got %v", c.Err())
+	}
+	ctx, cancel := c.WithTimeout(time.Hour)
+	defer cancel()
+	if ctx.Err() != nil {
+		t.Errorf("expected a live context, got %v", ctx.Err())
+	}
+}
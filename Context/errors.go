@@ -0,0 +1,164 @@
+package Context
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// HTTPError is an error carrying the HTTP response it should be rendered as.
+// It is returned by HandlerFuncE handlers and rendered by the error handler of the router.
+// The Status is the HTTP status code of the response, the Code a stable machine-readable identifier
+// such as "not_found", the Message a human-readable description sent to the client,
+// and Details any additional data sent along, such as the list of invalid fields.
+// The Err field holds the underlying error, if any, which is logged but never sent to the client.
+// The Type is the URI identifying the problem type in problem details responses, "about:blank" when empty.
+type HTTPError struct {
+	Status  int
+	Code    string
+	Message string
+	Details any
+	Err     error
+	Type    string
+}
+
+// Predefined errors for the most common HTTP error responses.
+// They can be returned as is, wrapped with fmt.Errorf and %w, or customized with the With methods,
+// and errors.Is reports whether an error matches one of them whatever its message, details or wrapped error.
+var (
+	ErrBadRequest            = NewHTTPError(http.StatusBadRequest, "bad_request", "Bad request")
+	ErrUnauthorized          = NewHTTPError(http.StatusUnauthorized, "unauthorized", "Unauthorized")
+	ErrForbidden             = NewHTTPError(http.StatusForbidden, "forbidden", "Forbidden")
+	ErrNotFound              = NewHTTPError(http.StatusNotFound, "not_found", "Resource not found")
+	ErrMethodNotAllowed      = NewHTTPError(http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed")
+	ErrConflict              = NewHTTPError(http.StatusConflict, "conflict", "Conflict")
+	ErrRequestEntityTooLarge = NewHTTPError(http.StatusRequestEntityTooLarge, "request_too_large", "Request body too large")
+	ErrUnsupportedMediaType  = NewHTTPError(http.StatusUnsupportedMediaType, "unsupported_media_type", "Unsupported media type")
+	ErrUnprocessableEntity   = NewHTTPError(http.StatusUnprocessableEntity, "unprocessable_entity", "Unprocessable entity")
+	ErrTooManyRequests       = NewHTTPError(http.StatusTooManyRequests, "too_many_requests", "Too many requests")
+	ErrInternalServerError   = NewHTTPError(http.StatusInternalServerError, "internal_error", "Internal server error")
+	ErrServiceUnavailable    = NewHTTPError(http.StatusServiceUnavailable, "service_unavailable", "Service unavailable")
+)
+
+// NewHTTPError creates a new HTTPError with the given status code, error code and message.
+func NewHTTPError(status int, code, message string) *HTTPError {
+	return &HTTPError{Status: status, Code: code, Message: message}
+}
+
+// Error returns the status code and message of the error, followed by the underlying error if any.
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("%d %s", e.Status, e.Message)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error, so that errors.Is and errors.As can inspect it.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is an HTTPError with the same status code and error code,
+// so that a customized copy of a predefined error still matches it with errors.Is.
+func (e *HTTPError) Is(target error) bool {
+	t, ok := target.(*HTTPError)
+	return ok && t.Status == e.Status && t.Code == e.Code
+}
+
+// WithMessage returns a copy of the error with the given message.
+func (e *HTTPError) WithMessage(message string) *HTTPError {
+	cp := *e
+	cp.Message = message
+	return &cp
+}
+
+// WithDetails returns a copy of the error with the given details.
+func (e *HTTPError) WithDetails(details any) *HTTPError {
+	cp := *e
+	cp.Details = details
+	return &cp
+}
+
+// Wrap returns a copy of the error wrapping the given underlying error,
+// which is logged by the default error handler but not sent to the client:
+//
+//	user, err := repo.Find(id)
+//	if err != nil {
+//		return context.ErrNotFound.Wrap(err)
+//	}
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	cp := *e
+	cp.Err = err
+	return &cp
+}
+
+// ErrorHandlerFunc is the signature of the function rendering the errors returned by HandlerFuncE handlers.
+type ErrorHandlerFunc func(*Context, error)
+
+// errorBody is the JSON body of the error responses, when problem details are not enabled.
+// The Code, Errors and Details members are omitted when empty, so that plain errors are rendered as {"error": message}.
+type errorBody struct {
+	Error   string       `json:"error"`
+	Code    string       `json:"code,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+	Details any          `json:"details,omitempty"`
+}
+
+// renderError aborts the handler chain and sends the error response.
+// The response is a problem details document when ProblemDetails is set, and a JSON error body otherwise.
+// Field errors given as Details are rendered in the "errors" member in both cases.
+func (c *Context) renderError(e *HTTPError) {
+	c.Abort()
+
+	var fields []FieldError
+	switch d := e.Details.(type) {
+	case ValidationErrors:
+		fields = d
+	case []FieldError:
+		fields = d
+	}
+
+	if c.ProblemDetails {
+		c.problem(e, fields)
+		return
+	}
+	body := errorBody{Error: e.Message, Code: e.Code, Errors: fields}
+	if fields == nil {
+		body.Details = e.Details
+	}
+	c.json(e.Status, body)
+}
+
+// DefaultErrorHandler renders the error returned by a handler as a JSON response,
+// or as a problem details response when ProblemDetails is set.
+// An HTTPError, possibly wrapped, is rendered with its status code, message, code and details,
+// and ValidationErrors are rendered as a 422 Unprocessable Entity response listing the invalid fields.
+// Any other error is rendered as a 500 Internal Server Error, without exposing its message to the client.
+// Server errors are logged along with their underlying error, and the handler chain is aborted.
+// Custom error handlers can map their own errors before falling back to it:
+//
+//	r.ErrorHandler = func(c *context.Context, err error) {
+//		if errors.Is(err, sql.ErrNoRows) {
+//			err = context.ErrNotFound.Wrap(err)
+//		}
+//		context.DefaultErrorHandler(c, err)
+//	}
+func DefaultErrorHandler(c *Context, err error) {
+	var httpErr *HTTPError
+	var validationErrs ValidationErrors
+	switch {
+	case errors.As(err, &httpErr):
+	case errors.As(err, &validationErrs):
+		httpErr = ErrUnprocessableEntity.WithMessage("Validation failed").WithDetails(validationErrs)
+	default:
+		httpErr = ErrInternalServerError.Wrap(err)
+	}
+
+	if httpErr.Status >= http.StatusInternalServerError {
+		log.Printf("[ERROR] [%s] %s %s - %v", c.RequestID(), c.Method, c.Path, err)
+	}
+
+	c.renderError(httpErr)
+}
@@ -0,0 +1,68 @@
+package Context
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDefaultErrorHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		body   string
+	}{
+		{"predefined error", ErrNotFound, http.StatusNotFound, `{"error":"Resource not found","code":"not_found"}`},
+		{"wrapped with fmt.Errorf", fmt.Errorf("loading user: %w", ErrForbidden), http.StatusForbidden, `{"error":"Forbidden","code":"forbidden"}`},
+		{"customized error", ErrConflict.WithMessage("Email already used").WithDetails(map[string]string{"email": "a@b.c"}),
+			http.StatusConflict, `{"error":"Email already used","code":"conflict","details":{"email":"a@b.c"}}`},
+		{"underlying error is not exposed", ErrServiceUnavailable.Wrap(errors.New("db down")),
+			http.StatusServiceUnavailable, `{"error":"Service unavailable","code":"service_unavailable"}`},
+		{"custom error", NewHTTPError(http.StatusPaymentRequired, "payment_required", "Upgrade your plan"),
+			http.StatusPaymentRequired, `{"error":"Upgrade your plan","code":"payment_required"}`},
+		{"validation errors", fmt.Errorf("binding: %w", ValidationErrors{{Field: "email", Rule: "required", Message: "is required"}}),
+			http.StatusUnprocessableEntity, `{"error":"Validation failed","code":"unprocessable_entity","errors":[{"field":"email","rule":"required","message":"is required"}]}`},
+		{"plain error", io.ErrUnexpectedEOF, http.StatusInternalServerError, `{"error":"Internal server error","code":"internal_error"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c := NewContext(w, httptest.NewRequest("GET", "/", nil))
+			DefaultErrorHandler(c, tt.err)
+			c.Writer.WriteHeaderNow()
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Body.String(); got != tt.body+"\n" {
+				t.Errorf("expected body %s, got %s", tt.body, got)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("expected a JSON response, got %s", got)
+			}
+			if !c.IsAborted() {
+				t.Error("expected the handler chain to be aborted")
+			}
+		})
+	}
+}
+
+func TestHTTPErrorIs(t *testing.T) {
+	err := fmt.Errorf("finding user: %w", ErrNotFound.WithMessage("User not found").Wrap(io.EOF))
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("expected a customized copy to match the predefined error")
+	}
+	if errors.Is(err, ErrBadRequest) {
+		t.Error("expected the error not to match another predefined error")
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Error("expected the underlying error to be reachable")
+	}
+	if got := err.Error(); got != "finding user: 404 User not found: EOF" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
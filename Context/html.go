@@ -0,0 +1,37 @@
+package Context
+
+import (
+	"bytes"
+	"errors"
+	"log"
+)
+
+// HTML renders the named template of the view engine of the router with the given data,
+// and sends it as a text/html response with the given status code.
+// The template is rendered before anything is written, so that a rendering error, such as a missing template
+// or a failing function, is logged and reported as a 500 Internal Server Error response rendered by renderError,
+// instead of a half-written page:
+//
+//	r.Views = router.NewViews("templates")
+//	r.GET("/admin/users", func(c *context.Context) {
+//		c.HTML(http.StatusOK, "admin/users", users)
+//	})
+func (c *Context) HTML(status int, name string, data any) {
+	var buf bytes.Buffer
+	err := errors.New("no view engine configured on the router")
+	if c.Views != nil {
+		err = c.Views.ExecuteTemplate(&buf, name, data)
+	}
+	if err != nil {
+		log.Printf("[ERROR] [%s] %s %s - cannot render template %q: %v", c.RequestID(), c.Method, c.Path, name, err)
+		c.renderError(ErrInternalServerError.Wrap(err))
+		return
+	}
+
+	if !c.checkWritable(status) {
+		return
+	}
+	c.Writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	c.SetStatus(status)
+	c.Writer.Write(buf.Bytes())
+}
@@ -2,13 +2,26 @@ package Context
 
 import "encoding/json"
 
-// BindJSON binds the request body to the provided object.
-// It uses the json package to decode the JSON data from the request body into the specified object.
-// If there is an error during decoding, it returns the error.
-// The object must be a pointer to a struct or a map that matches the JSON structure.
+// BindJSON decodes the JSON body of the request into the provided object,
+// which must be a pointer to a struct or a map that matches the JSON structure.
+// The body is read as by BindBody: it is limited to BodyConfig.MaxBytes, it must hold a single JSON document,
+// and unknown fields are rejected when BodyConfig.DisallowUnknownFields is set.
+// A body that is too large or malformed is reported as an HTTPError, and the fields whose JSON value
+// does not fit their type, or unknown fields, as ValidationErrors, both rendered by the DefaultErrorHandler.
+// Unlike BindBody, it ignores the Content-Type of the request and does not check the validate tags.
 func (c *Context) BindJSON(obj any) error {
-	decoder := json.NewDecoder(c.Request.Body)
-	return decoder.Decode(obj)
+	body, err := c.readBody()
+	if err != nil {
+		return err
+	}
+	errs, err := decodeJSON(body, obj, c.BodyConfig.DisallowUnknownFields)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
 // json sends a JSON response with the specified status code and message.
@@ -17,10 +30,20 @@ func (c *Context) BindJSON(obj any) error {
 // It uses the json package to encode the message into JSON format and writes it to the response writer.
 // This function is typically used to send structured data back to the client in a JSON format.
 // The status code indicates the HTTP status of the response, such as 200 for success or 404 for not found.
+// Nothing is sent if a response was already written for the request.
 func (c *Context) json(status int, message any) {
-	c.Writer.Header().Set("Content-Type", "application/json")
+	c.writeJSON(status, "application/json", message)
+}
+
+// writeJSON sends the message encoded as JSON with the given status code and content type,
+// such as "application/problem+json" for problem details.
+// Nothing is sent if a response was already written for the request.
+func (c *Context) writeJSON(status int, contentType string, message any) {
+	if !c.checkWritable(status) {
+		return
+	}
+	c.Writer.Header().Set("Content-Type", contentType)
 	c.SetStatus(status)
-	c.Writer.WriteHeader(status)
 	json.NewEncoder(c.Writer).Encode(message)
 }
 
@@ -29,8 +52,9 @@ func (c *Context) json(status int, message any) {
 // The message is a string that describes the error or issue encountered.
 // This function is typically used to handle errors in a consistent manner, providing a structured JSON response
 // to the client when an error occurs.
+// When ProblemDetails is set, the error is sent as an RFC 9457 problem details response instead,
+// with the message as detail and the status text as title.
+// It also stops the handler chain with Abort, so that the remaining middlewares and the handler are skipped.
 func (c *Context) abortWithStatusJSON(status int, message string) {
-	c.json(status, map[string]string{
-		"error": message,
-	})
+	c.renderError(&HTTPError{Status: status, Message: message})
 }
@@ -0,0 +1,50 @@
+package Context
+
+import (
+	"context"
+	"net/http"
+)
+
+// Get returns the value of the first parameter with the given name,
+// and a boolean indicating whether such a parameter was found.
+func (ps Params) Get(key string) (string, bool) {
+	for i := range ps {
+		if ps[i].Key == key {
+			return ps[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// Map returns the parameters as a map keyed by their name.
+// It allocates a new map on each call, and is mostly useful to hand the parameters over to other code.
+func (ps Params) Map() map[string]string {
+	m := make(map[string]string, len(ps))
+	for _, p := range ps {
+		m[p.Key] = p.Value
+	}
+	return m
+}
+
+// AddParam appends a path parameter to the Context's Params.
+// It is used by the router to store the parameters captured from the request path and host.
+func (c *Context) AddParam(key, value string) {
+	c.Params = append(c.Params, Param{Key: key, Value: value})
+}
+
+// requestParamsKey is the key under which path parameters are stored in the context of a request.
+type requestParamsKey struct{}
+
+// WithRequestParams returns a shallow copy of the request carrying the given path parameters in its context.
+// It is used when a request is handed over to a mounted http.Handler, such as a sub-router,
+// so that the parameters matched by the parent router remain available.
+func WithRequestParams(r *http.Request, params map[string]string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), requestParamsKey{}, params))
+}
+
+// RequestParams returns the path parameters stored in the request by WithRequestParams, or nil if there are none.
+// It allows plain http.Handler mounted on a router to read the parameters of the mount prefix.
+func RequestParams(r *http.Request) map[string]string {
+	params, _ := r.Context().Value(requestParamsKey{}).(map[string]string)
+	return params
+}
@@ -0,0 +1,106 @@
+package Context
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"sync"
+)
+
+// contextPool holds the contexts released by ReleaseContext, to be reused by AcquireContext.
+var contextPool = sync.Pool{
+	New: func() any {
+		return new(Context)
+	},
+}
+
+// AcquireContext returns a Context for the given response writer and request, taken from a pool when available.
+// It behaves like NewContext, but the Context must be given back with ReleaseContext once the request is served.
+// The router acquires and releases the contexts of the requests it serves.
+func AcquireContext(w http.ResponseWriter, r *http.Request) *Context {
+	c := contextPool.Get().(*Context)
+	c.reset(w, r)
+	return c
+}
+
+// ReleaseContext puts the Context back into the pool, so that it can be reused by another request.
+// The Context, its Params and its Data must not be used anymore once released:
+// handlers that need them after they return, for example in a goroutine, must use a copy made with Copy.
+// The contexts derived with WithCancel, WithTimeout and WithDeadline do not depend on the Context and stay valid.
+func ReleaseContext(c *Context) {
+	c.reset(nil, nil)
+	contextPool.Put(c)
+}
+
+// reset clears the Context and prepares it for the given response writer and request.
+// The Params keep their backing array and the Data map is emptied rather than dropped,
+// so that a reused Context does not allocate for them again.
+// Parameters stored in the request by a parent router, see WithRequestParams, are copied into Params.
+func (c *Context) reset(w http.ResponseWriter, r *http.Request) {
+	c.writer.reset(w)
+	c.Writer = nil
+	if w != nil {
+		c.Writer = &c.writer
+	}
+	c.Request = r
+	c.Router = nil
+	c.Views = nil
+	c.Path = ""
+	c.Method = ""
+	c.Route = ""
+	c.RouteName = ""
+	c.DevMode = false
+	c.ProblemDetails = false
+	c.BodyConfig = BodyConfig{}
+	c.aborted = false
+	c.paramsBuf = [len(c.paramsBuf)]Param{}
+	c.Params = c.paramsBuf[:0]
+	clear(c.Data)
+
+	if r != nil {
+		c.Path = r.URL.Path
+		c.Method = r.Method
+		for k, v := range RequestParams(r) {
+			c.AddParam(k, v)
+		}
+	}
+}
+
+// Copy returns a copy of the Context that can safely be used after the handler returns,
+// typically from a goroutine started by the handler.
+// The Params and Data of the copy are independent from the ones of the Context, which is reused for another request.
+// The Writer of the copy only reports the status and size of the response at the time of the copy,
+// and must not be written to since the response is sent once the handler returns.
+// The request context of the copy is not canceled when the request completes, so that background work is not interrupted:
+//
+//	cc := c.Copy()
+//	go func() {
+//		sendWelcomeEmail(cc, cc.Param("id"))
+//	}()
+func (c *Context) Copy() *Context {
+	cp := &Context{
+		Router:    c.Router,
+		Views:     c.Views,
+		Path:      c.Path,
+		Method:    c.Method,
+		Route:     c.Route,
+		RouteName: c.RouteName,
+
+		DevMode:        c.DevMode,
+		ProblemDetails: c.ProblemDetails,
+		BodyConfig:     c.BodyConfig,
+
+		Params: append(Params(nil), c.Params...),
+		Data:   maps.Clone(c.Data),
+
+		aborted: c.aborted,
+	}
+	if c.Writer != nil {
+		cp.writer = responseWriter{status: c.Writer.Status(), size: c.Writer.Size(), written: c.Writer.Written()}
+		cp.Writer = &cp.writer
+	}
+	if c.Request != nil {
+		cp.Request = c.Request.WithContext(context.WithoutCancel(c.Request.Context()))
+	}
+	return cp
+}
@@ -0,0 +1,98 @@
+package Context
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// problemContentType is the media type of the problem details responses defined by RFC 9457.
+const problemContentType = "application/problem+json"
+
+// Problem is the body of a problem details response, as defined by RFC 9457.
+// Type is a URI identifying the problem type, "about:blank" when the problem has no other semantics than its status code,
+// Title the short summary of the problem type, Status the HTTP status code, Detail the explanation of this occurrence,
+// and Instance a URI identifying this occurrence, built from the request ID.
+// Code, Errors and Details are extension members carrying the error code, the invalid fields
+// and any additional data of the error.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	Details  any          `json:"details,omitempty"`
+}
+
+// FieldError describes why a field of the request is invalid.
+// Field is the name of the field, such as "email" or "address.city", Rule the identifier of the failed rule,
+// such as "required", and Message the human-readable description of the error.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error returns the field name followed by the message.
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors is a list of field errors, returned when a request fails validation.
+// Returned by a HandlerFuncE, it is rendered by DefaultErrorHandler as a 422 Unprocessable Entity response
+// listing the invalid fields.
+type ValidationErrors []FieldError
+
+// Error returns the errors of all the fields, separated by semicolons.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ErrorValidation sends a 422 Unprocessable Entity response listing the invalid fields of the request.
+// The message describes the error as a whole, and the fields are rendered in the "errors" member of the response.
+func (c *Context) ErrorValidation(msg string, errs []FieldError) {
+	c.renderError(ErrUnprocessableEntity.WithMessage(msg).WithDetails(ValidationErrors(errs)))
+}
+
+// problemInstance returns the URI identifying the current occurrence of a problem, built from the request ID.
+// UUIDs, such as the ones generated by the RequestIDMiddleware, are rendered as "urn:uuid:" URNs.
+// It returns an empty string when the request has no ID.
+func (c *Context) problemInstance() string {
+	id := c.RequestID()
+	if id == "" {
+		return ""
+	}
+	if _, err := uuid.Parse(id); err == nil {
+		return "urn:uuid:" + id
+	}
+	return "urn:request-id:" + url.PathEscape(id)
+}
+
+// problem sends the error as a problem details response, with the application/problem+json content type.
+func (c *Context) problem(e *HTTPError, fields []FieldError) {
+	typ := e.Type
+	if typ == "" {
+		typ = "about:blank"
+	}
+	p := Problem{
+		Type:     typ,
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   e.Message,
+		Instance: c.problemInstance(),
+		Code:     e.Code,
+		Errors:   fields,
+	}
+	if fields == nil {
+		p.Details = e.Details
+	}
+	c.writeJSON(e.Status, problemContentType, p)
+}
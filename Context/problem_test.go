@@ -0,0 +1,81 @@
+package Context
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblemDetails(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		id      string
+		problem Problem
+	}{
+		{"predefined error", ErrNotFound.WithMessage("User 42 not found"), "3f2b8e0c-1b9e-4c1e-9d0e-7a6b5c4d3e2f", Problem{
+			Type: "about:blank", Title: "Not Found", Status: http.StatusNotFound, Detail: "User 42 not found",
+			Instance: "urn:uuid:3f2b8e0c-1b9e-4c1e-9d0e-7a6b5c4d3e2f", Code: "not_found",
+		}},
+		{"problem type", &HTTPError{Status: http.StatusForbidden, Code: "out_of_credit", Message: "Your balance is 30", Type: "https://example.com/probs/out-of-credit"},
+			"req/1", Problem{
+				Type: "https://example.com/probs/out-of-credit", Title: "Forbidden", Status: http.StatusForbidden,
+				Detail: "Your balance is 30", Instance: "urn:request-id:req%2F1", Code: "out_of_credit",
+			}},
+		{"validation errors", ValidationErrors{{Field: "email", Rule: "email", Message: "must be a valid email"}}, "", Problem{
+			Type: "about:blank", Title: "Unprocessable Entity", Status: http.StatusUnprocessableEntity, Detail: "Validation failed",
+			Code: "unprocessable_entity", Errors: []FieldError{{Field: "email", Rule: "email", Message: "must be a valid email"}},
+		}},
+		{"plain error", errors.New("secret"), "", Problem{
+			Type: "about:blank", Title: "Internal Server Error", Status: http.StatusInternalServerError,
+			Detail: "Internal server error", Code: "internal_error",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c := NewContext(w, httptest.NewRequest("GET", "/", nil))
+			c.ProblemDetails = true
+			if tt.id != "" {
+				c.Set("request_id", tt.id)
+			}
+			DefaultErrorHandler(c, tt.err)
+			c.Writer.WriteHeaderNow()
+
+			if w.Code != tt.problem.Status {
+				t.Errorf("expected status %d, got %d", tt.problem.Status, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+				t.Errorf("expected the problem details content type, got %s", got)
+			}
+			var members map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &members); err != nil {
+				t.Fatal(err)
+			}
+			for _, member := range []string{"type", "title", "status"} {
+				if _, ok := members[member]; !ok {
+					t.Errorf("expected the %s member in %s", member, w.Body)
+				}
+			}
+			want, _ := json.Marshal(tt.problem)
+			if got := w.Body.String(); got != string(want)+"\n" {
+				t.Errorf("expected %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestErrorHelpersWithProblemDetails(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest("GET", "/", nil))
+	c.ProblemDetails = true
+	c.ErrorBadRequest("Missing id")
+	c.Writer.WriteHeaderNow()
+
+	want := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Missing id"}` + "\n"
+	if w.Code != http.StatusBadRequest || w.Body.String() != want || w.Header().Get("Content-Type") != "application/problem+json" {
+		t.Errorf("expected %s, got %d %s: %s", want, w.Code, w.Header().Get("Content-Type"), w.Body)
+	}
+}
@@ -0,0 +1,430 @@
+package Context
+
+import (
+	"bytes"
+	"cmp"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// RendererFunc encodes data to w in the format of the media type it is registered for with RegisterRenderer.
+type RendererFunc func(w io.Writer, data any) error
+
+var (
+	// renderersMu guards renderers, rendererAliases and rendererOrder.
+	renderersMu sync.RWMutex
+	// renderers holds the renderers registered for each media type.
+	renderers = make(map[string]RendererFunc)
+	// rendererAliases holds the media type each alias registered with RegisterRendererAlias stands for.
+	rendererAliases = make(map[string]string)
+	// rendererOrder lists the media types of the renderers and of the aliases in registration order,
+	// which is the order of preference when the client accepts several of them equally.
+	rendererOrder []string
+)
+
+func init() {
+	RegisterRenderer("application/json", renderJSON)
+	RegisterRenderer("application/xml", renderXML)
+	RegisterRenderer("application/yaml", renderYAML)
+	RegisterRenderer("application/msgpack", renderMsgpack)
+	RegisterRenderer("application/cbor", renderCBOR)
+	RegisterRenderer("text/plain", renderText)
+	RegisterRenderer("text/csv", renderCSV)
+	RegisterRendererAlias("text/xml", "application/xml")
+	RegisterRendererAlias("application/x-yaml", "application/yaml")
+	RegisterRendererAlias("text/yaml", "application/yaml")
+	RegisterRendererAlias("application/x-msgpack", "application/msgpack")
+	RegisterRendererAlias("application/vnd.msgpack", "application/msgpack")
+}
+
+// parseRendererType parses the media type a renderer or an alias is registered for,
+// and reports false if it is malformed or is a media range such as "text/*".
+func parseRendererType(mediaType string) (string, bool) {
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	if err != nil || !strings.Contains(parsed, "/") || strings.Contains(parsed, "*") {
+		return "", false
+	}
+	return parsed, true
+}
+
+// RegisterRenderer registers the renderer used by Render and Negotiate for the given media type,
+// such as "application/vnd.api+json", replacing the renderer already registered for it if any.
+// JSON, XML, YAML, MessagePack, CBOR, plain text and CSV renderers are registered by default,
+// and are preferred in that order when the client accepts any of them.
+// Renderers are typically registered at startup, before the server handles requests.
+// It panics if the media type is malformed or the renderer is nil.
+func RegisterRenderer(mediaType string, fn RendererFunc) {
+	parsed, ok := parseRendererType(mediaType)
+	if !ok || fn == nil {
+		panic(fmt.Sprintf("render: cannot register a renderer for %q", mediaType))
+	}
+
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	if _, ok := renderers[parsed]; !ok && rendererAliases[parsed] == "" {
+		rendererOrder = append(rendererOrder, parsed)
+	}
+	delete(rendererAliases, parsed)
+	renderers[parsed] = fn
+}
+
+// RegisterRendererAlias registers another media type for the renderer of mediaType,
+// such as "text/xml" for "application/xml", so that clients accepting the alias are served by the same renderer.
+// Responses negotiated through an alias are sent with the alias as Content-Type,
+// but aliases are not listed in the 406 Not Acceptable response of Negotiate.
+// It panics if one of the media types is malformed or if no renderer is registered for mediaType.
+func RegisterRendererAlias(alias, mediaType string) {
+	parsedAlias, ok := parseRendererType(alias)
+	parsed, ok2 := parseRendererType(mediaType)
+
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	if canonical, isAlias := rendererAliases[parsed]; isAlias {
+		parsed = canonical
+	}
+	if !ok || !ok2 || renderers[parsed] == nil || parsedAlias == parsed {
+		panic(fmt.Sprintf("render: cannot register %q as an alias of %q", alias, mediaType))
+	}
+	if _, ok := renderers[parsedAlias]; !ok && rendererAliases[parsedAlias] == "" {
+		rendererOrder = append(rendererOrder, parsedAlias)
+	}
+	delete(renderers, parsedAlias)
+	rendererAliases[parsedAlias] = parsed
+}
+
+// renderer returns the renderer registered for the media type or for the media type it is an alias of,
+// or nil if there is none. The caller must hold renderersMu.
+func renderer(mediaType string) RendererFunc {
+	if canonical, ok := rendererAliases[mediaType]; ok {
+		mediaType = canonical
+	}
+	return renderers[mediaType]
+}
+
+// Render sends data encoded with the renderer registered for the media type, with the given status code.
+// The data is encoded before anything is written, so that an encoding error can still be reported
+// as a 500 Internal Server Error response, rendered by renderError.
+// It panics if no renderer is registered for the media type.
+func (c *Context) Render(status int, mediaType string, data any) {
+	renderersMu.RLock()
+	fn := renderer(mediaType)
+	renderersMu.RUnlock()
+	if fn == nil {
+		panic(fmt.Sprintf("render: no renderer registered for %q", mediaType))
+	}
+
+	var buf bytes.Buffer
+	if err := fn(&buf, data); err != nil {
+		log.Printf("[ERROR] [%s] %s %s - cannot render %s: %v", c.RequestID(), c.Method, c.Path, mediaType, err)
+		c.renderError(ErrInternalServerError.Wrap(err))
+		return
+	}
+	c.writeRendered(status, mediaType, buf.Bytes())
+}
+
+// writeRendered sends the body encoded for the media type with the given status code,
+// unless a response was already written.
+func (c *Context) writeRendered(status int, mediaType string, body []byte) {
+	if !c.checkWritable(status) {
+		return
+	}
+	contentType := mediaType
+	if strings.HasPrefix(mediaType, "text/") {
+		contentType += "; charset=utf-8"
+	}
+	c.Writer.Header().Set("Content-Type", contentType)
+	c.SetStatus(status)
+	c.Writer.Write(body)
+}
+
+// Negotiate sends data in the format preferred by the client among the registered renderers,
+// according to the media types and q-values of the Accept header of the request, with the given status code.
+// The media types accepted by the client are tried in the order given by NegotiateMediaTypes,
+// and the next one is tried when a renderer cannot encode the data, such as a map with the XML renderer,
+// or a value that is not a slice with the CSV renderer.
+// The Vary header is set to Accept, so that caches store each format separately.
+// When the client accepts none of the registered media types, or none of their renderers can encode the data,
+// it responds with ErrorNotAcceptable, listing the registered media types without their aliases:
+//
+//	c.Negotiate(http.StatusOK, users) // JSON, XML, YAML, CSV... depending on the Accept header
+func (c *Context) Negotiate(status int, data any) {
+	c.Writer.Header().Add("Vary", "Accept")
+
+	for _, mediaType := range c.NegotiateMediaTypes() {
+		renderersMu.RLock()
+		fn := renderer(mediaType)
+		renderersMu.RUnlock()
+		if fn == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := fn(&buf, data); err != nil {
+			if c.DevMode {
+				log.Printf("[WARN] [%s] %s %s - cannot render %s, trying the next accepted media type: %v",
+					c.RequestID(), c.Method, c.Path, mediaType, err)
+			}
+			continue
+		}
+		c.writeRendered(status, mediaType, buf.Bytes())
+		return
+	}
+
+	renderersMu.RLock()
+	var available []string
+	for _, mediaType := range rendererOrder {
+		if _, isAlias := rendererAliases[mediaType]; !isAlias {
+			available = append(available, mediaType)
+		}
+	}
+	renderersMu.RUnlock()
+	c.ErrorNotAcceptable("None of the accepted media types can be produced, available media types are: " + strings.Join(available, ", "))
+}
+
+// NegotiateMediaType returns the registered media type preferred by the client according to the Accept header,
+// and reports false if the client accepts none of them.
+func (c *Context) NegotiateMediaType() (string, bool) {
+	mediaTypes := c.NegotiateMediaTypes()
+	if len(mediaTypes) == 0 {
+		return "", false
+	}
+	return mediaTypes[0], true
+}
+
+// NegotiateMediaTypes returns the registered media types accepted by the client, from the most to the least preferred.
+// The most specific media range of the Accept header gives the q-value of each media type,
+// and media types with a q-value of 0 are not acceptable.
+// Ties are won by the media types named explicitly in the Accept header over those matched by "type/*",
+// which win over those matched by "*/*", then by the renderers registered first,
+// so that the default JSON renderer is preferred when the client accepts anything,
+// and is the only media type returned when the request has no Accept header.
+func (c *Context) NegotiateMediaTypes() []string {
+	ranges := parseAccept(c.Request.Header.Get("Accept"))
+
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	if len(ranges) == 0 {
+		return rendererOrder[:1:1]
+	}
+
+	type candidate struct {
+		mediaType   string
+		q           float64
+		specificity int
+	}
+	var candidates []candidate
+	for _, mediaType := range rendererOrder {
+		if q, s := acceptQuality(ranges, mediaType); q > 0 {
+			candidates = append(candidates, candidate{mediaType, q, s})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if n := cmp.Compare(b.q, a.q); n != 0 {
+			return n
+		}
+		return cmp.Compare(b.specificity, a.specificity)
+	})
+
+	mediaTypes := make([]string, len(candidates))
+	for i, cand := range candidates {
+		mediaTypes[i] = cand.mediaType
+	}
+	return mediaTypes
+}
+
+// acceptRange is a media range of an Accept header, such as "text/*", with its q-value.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses the media ranges of an Accept header, ignoring the malformed ones.
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType, q})
+	}
+	return ranges
+}
+
+// acceptQuality returns the q-value given by the most specific media range matching the media type,
+// along with its specificity: 2 for an exact match, 1 for "type/*" and 0 for "*/*".
+// It returns a q-value of 0 and a specificity of -1 if no media range matches.
+func acceptQuality(ranges []acceptRange, mediaType string) (float64, int) {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		s := -1
+		switch {
+		case r.mediaType == mediaType:
+			s = 2
+		case r.mediaType == typ+"/*":
+			s = 1
+		case r.mediaType == "*/*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q, specificity
+}
+
+// renderJSON encodes data as JSON.
+func renderJSON(w io.Writer, data any) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
+// renderXML encodes data as an XML document, with the XML header.
+// Slices and arrays are wrapped in an <items> root element, so that the document has a single root.
+func renderXML(w io.Writer, data any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	v := reflect.ValueOf(data)
+	if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Type().Elem().Kind() == reflect.Uint8 {
+		return enc.Encode(data)
+	}
+
+	root := xml.StartElement{Name: xml.Name{Local: "items"}}
+	if err := enc.EncodeToken(root); err != nil {
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := enc.Encode(v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	if err := enc.EncodeToken(root.End()); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// renderYAML encodes data as YAML.
+func renderYAML(w io.Writer, data any) error {
+	enc := yaml.NewEncoder(w)
+	if err := enc.Encode(data); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// renderMsgpack encodes data as MessagePack, naming struct fields after their json tags.
+func renderMsgpack(w io.Writer, data any) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc.Encode(data)
+}
+
+// renderCBOR encodes data as CBOR, naming struct fields after their cbor or json tags.
+func renderCBOR(w io.Writer, data any) error {
+	return cbor.NewEncoder(w).Encode(data)
+}
+
+// renderText writes data as plain text: strings and byte slices as is,
+// types implementing fmt.Stringer or encoding.TextMarshaler through their methods, and other values with fmt.
+func renderText(w io.Writer, data any) error {
+	var err error
+	switch v := data.(type) {
+	case string:
+		_, err = io.WriteString(w, v)
+	case []byte:
+		_, err = w.Write(v)
+	case fmt.Stringer:
+		_, err = io.WriteString(w, v.String())
+	case encoding.TextMarshaler:
+		var text []byte
+		if text, err = v.MarshalText(); err == nil {
+			_, err = w.Write(text)
+		}
+	default:
+		_, err = fmt.Fprint(w, v)
+	}
+	return err
+}
+
+// renderCSV writes data as CSV. It accepts [][]string records, and slices of structs,
+// rendered with a header row made of the csv tags of the fields, or their names, followed by one row per element.
+func renderCSV(w io.Writer, data any) error {
+	cw := csv.NewWriter(w)
+	if records, ok := data.([][]string); ok {
+		return cw.WriteAll(records)
+	}
+
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("csv: cannot render %T, expected [][]string or a slice of structs", data)
+	}
+	elemType := v.Type().Elem()
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("csv: cannot render %T, expected [][]string or a slice of structs", data)
+	}
+
+	var header []string
+	var index []int
+	for i := 0; i < elemType.NumField(); i++ {
+		sf := elemType.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("csv"), ",")
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		header = append(header, name)
+		index = append(index, i)
+	}
+
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	row := make([]string, len(index))
+	for i := 0; i < v.Len(); i++ {
+		elem := derefValue(v.Index(i))
+		for j, fi := range index {
+			row[j] = ""
+			if elem.IsValid() {
+				if f := derefValue(elem.Field(fi)); f.IsValid() {
+					row[j] = fmt.Sprint(f.Interface())
+				}
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
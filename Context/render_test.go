@@ -0,0 +1,129 @@
+package Context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseAccept(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []acceptRange
+	}{
+		{"", nil},
+		{"application/json", []acceptRange{{"application/json", 1}}},
+		{"text/*;q=0.5, */*;q=0.1", []acceptRange{{"text/*", 0.5}, {"*/*", 0.1}}},
+		{"application/xml;q=0", []acceptRange{{"application/xml", 0}}},
+		{"Application/JSON ; Q=0.7", []acceptRange{{"application/json", 0.7}}},
+		{"application/json;q=2, text/csv;q=abc, text/plain", []acceptRange{{"text/plain", 1}}},
+		{"not a media type, , text/csv", []acceptRange{{"text/csv", 1}}},
+	}
+	for _, tt := range tests {
+		if got := parseAccept(tt.header); !slices.Equal(got, tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.header, tt.want, got)
+		}
+	}
+}
+
+func TestNegotiateMediaTypes(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   []string
+	}{
+		{"", []string{"application/json"}},
+		{"text/csv", []string{"text/csv"}},
+		{"text/xml", []string{"text/xml"}},
+		{"image/png", nil},
+		{"*/*;q=0", nil},
+		// The most specific media range gives the q-value: q=0 excludes XML even though */* accepts it.
+		{"application/xml;q=0, */*", []string{"application/json", "application/yaml", "application/msgpack",
+			"application/cbor", "text/plain", "text/csv", "text/xml", "application/x-yaml", "text/yaml",
+			"application/x-msgpack", "application/vnd.msgpack"}},
+		{"text/*", []string{"text/plain", "text/csv", "text/xml", "text/yaml"}},
+		{"text/*;q=0.5, text/csv", []string{"text/csv", "text/plain", "text/xml", "text/yaml"}},
+		// Ties are won by explicit media types, then by wildcard matches in registration order.
+		{"text/*, application/yaml", []string{"application/yaml", "text/plain", "text/csv", "text/xml", "text/yaml"}},
+		{"application/yaml, application/xml", []string{"application/xml", "application/yaml"}},
+		{"text/csv;q=0.5, application/json;q=0.9", []string{"application/json", "text/csv"}},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		c := NewContext(httptest.NewRecorder(), req)
+		if got := c.NegotiateMediaTypes(); !slices.Equal(got, tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.accept, tt.want, got)
+		}
+	}
+}
+
+// browserAccept is the default Accept header of web browsers.
+const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+func TestNegotiate(t *testing.T) {
+	type user struct {
+		ID   int    `json:"id" xml:"id" csv:"id"`
+		Name string `json:"name" xml:"name" csv:"name"`
+	}
+	tests := []struct {
+		name        string
+		accept      string
+		data        any
+		status      int
+		contentType string
+	}{
+		{"no accept header", "", map[string]any{"id": 1}, http.StatusOK, "application/json"},
+		{"browser with a struct", browserAccept, user{1, "Ada"}, http.StatusOK, "application/xml"},
+		{"browser with a map falls back to JSON", browserAccept, map[string]any{"id": 1}, http.StatusOK, "application/json"},
+		{"csv with a slice", "text/csv", []user{{1, "Ada"}}, http.StatusOK, "text/csv; charset=utf-8"},
+		{"csv with a struct", "text/csv", user{1, "Ada"}, http.StatusNotAcceptable, "application/json"},
+		{"csv with a struct falls back", "text/csv, */*;q=0.1", user{1, "Ada"}, http.StatusOK, "application/json"},
+		{"xml and csv with a map", "application/xml, text/csv", map[string]any{"id": 1}, http.StatusNotAcceptable, "application/json"},
+		{"unsupported media type", "image/png", user{1, "Ada"}, http.StatusNotAcceptable, "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			c := NewContext(w, req)
+			c.Negotiate(http.StatusOK, tt.data)
+			c.Writer.WriteHeaderNow()
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("expected Content-Type %s, got %s", tt.contentType, got)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("expected Vary: Accept, got %q", got)
+			}
+		})
+	}
+}
+
+func TestNegotiateNotAcceptableListsCanonicalTypes(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "image/png")
+	w := httptest.NewRecorder()
+	NewContext(w, req).Negotiate(http.StatusOK, "data")
+
+	body := w.Body.String()
+	for _, mediaType := range []string{"application/json", "application/xml", "text/csv"} {
+		if !strings.Contains(body, mediaType) {
+			t.Errorf("expected %s to be listed in %s", mediaType, body)
+		}
+	}
+	for _, alias := range []string{"text/xml", "application/x-yaml", "application/vnd.msgpack"} {
+		if strings.Contains(body, alias) {
+			t.Errorf("expected the alias %s not to be listed in %s", alias, body)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package Context
 
 import (
+	"context"
+	"io"
 	"mime/multipart"
 	"net/http"
 )
@@ -14,18 +16,56 @@ import (
 // for handling HTTP requests in a web application.
 // It is typically created at the beginning of request processing and passed through the middleware chain
 // and to the final handler.
+// The Writer records the status code and size of the response, see ResponseWriter,
+// and is backed by the writer field so that it is not allocated for each request.
+// The Router field gives access to the router serving the request, to build URLs from named routes.
+// The Views field holds the HTML view engine of the router, used by HTML to render templates, if the router has one.
+// The Route and RouteName fields hold the pattern and the name of the matched route, such as "/users/:id",
+// and are empty when no route matched the request, for example in not found handlers.
+// DevMode is set when the router serving the request runs in development mode,
+// where misuses such as responding twice to a request are reported in the logs.
+// ProblemDetails is set when the router renders errors as RFC 9457 problem details, see Router.ProblemDetails.
+// BodyConfig holds the limits applied when reading the request body, see Router.BodyConfig.
+// The aborted flag is set by Abort to skip the rest of the handler chain.
+// The Params slice holds the path parameters in the order they appear in the route, backed by the paramsBuf array
+// so that routes with up to three parameters do not allocate.
+// The Data map is only allocated when a value is first stored with Set.
+// Contexts served by the router are pooled and reused once the handler chain returns,
+// so handlers starting goroutines must hand them a copy made with Copy.
 type Context struct {
-	Writer  http.ResponseWriter
+	Writer  ResponseWriter
 	Request *http.Request
+	Router  URLBuilder
+	Views   HTMLRenderer
 
-	Path   string
-	Method string
-	Status int
+	Path      string
+	Method    string
+	Route     string
+	RouteName string
 
-	Params map[string]string
+	DevMode        bool
+	ProblemDetails bool
+	BodyConfig     BodyConfig
+
+	Params Params
 	Data   map[string]any
+
+	aborted   bool
+	writer    responseWriter
+	paramsBuf [3]Param
+}
+
+// Param is a path parameter captured by the router, made of its name and its value.
+type Param struct {
+	Key   string
+	Value string
 }
 
+// Params is the list of path parameters of a request, in the order they appear in the route.
+// A slice is used instead of a map since routes rarely have more than a few parameters,
+// for which a linear search is faster than hashing and does not allocate.
+type Params []Param
+
 // UploadedFile represents a file that has been uploaded in an HTTP request.
 // It contains the file itself, its header, filename, and size.
 // This struct is used to handle file uploads in web applications.
@@ -42,6 +82,27 @@ type UploadedFile struct {
 	Size       int64
 }
 
+// Context implements the standard context.Context interface, so that it can be passed directly
+// to functions expecting one, such as database drivers or HTTP clients.
+var _ context.Context = (*Context)(nil)
+
 // HandlerFunc is a function type that defines the signature for HTTP handlers.
 // It takes a pointer to a Context as an argument, allowing access to the request and response data.
 type HandlerFunc func(*Context)
+
+// HandlerFuncE is a handler returning an error, accepted by the router in place of a HandlerFunc.
+// The returned error is rendered by the error handler of the router, so that handlers can simply return
+// errors such as ErrNotFound instead of writing the error response themselves.
+type HandlerFuncE func(*Context) error
+
+// URLBuilder is the interface implemented by the router to build URLs from named routes.
+// It is exposed on the Context so that handlers can generate links without hard-coding paths.
+type URLBuilder interface {
+	URL(name string, params map[string]string) (string, error)
+}
+
+// HTMLRenderer is the interface implemented by the view engine of the router to render HTML templates by name.
+// It is exposed on the Context so that handlers can render pages with HTML.
+type HTMLRenderer interface {
+	ExecuteTemplate(w io.Writer, name string, data any) error
+}
@@ -0,0 +1,561 @@
+package Context
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	helpers "github.com/ines-mgg/LetsGoBack/Helpers"
+)
+
+// ValidationFunc is a custom validation rule registered with RegisterValidation.
+// It receives the value of the field, dereferenced if it is a pointer, and the parameter of the rule,
+// such as "5" for "divisibleby=5", and reports whether the value is valid.
+type ValidationFunc func(value any, param string) bool
+
+// customRule is a validation rule registered with RegisterValidation, along with its error message.
+type customRule struct {
+	fn      ValidationFunc
+	message string
+}
+
+// validationRule is a rule of a validate tag, such as "min=3", split into its name and parameter.
+type validationRule struct {
+	name  string
+	param string
+}
+
+// validateField describes a field of a struct checked by Validate.
+// The index is the position of the field in its struct, and name its name in the error messages,
+// taken from its json or binding tag if any.
+// The rules apply to the field itself, and the dive rules, given after "dive" in the tag,
+// to each element of a slice, array or map field.
+// The embedded field is set for embedded structs, whose fields are reported without prefix.
+type validateField struct {
+	index    int
+	name     string
+	rules    []validationRule
+	dive     []validationRule
+	hasDive  bool
+	embedded bool
+}
+
+// builtinRules holds the validation rules known by Validate, keyed by name.
+// Each rule receives the struct holding the field, for the rules comparing fields, the value of the field and the parameter.
+var builtinRules = map[string]func(parent, v reflect.Value, param string) bool{
+	"required": func(_, v reflect.Value, _ string) bool { return !isEmptyValue(v) },
+	"email": func(_, v reflect.Value, _ string) bool {
+		return v.Kind() == reflect.String && helpers.IsValidEmail(v.String())
+	},
+	"url": func(_, v reflect.Value, _ string) bool {
+		return v.Kind() == reflect.String && helpers.IsValidURL(v.String())
+	},
+	"password": func(_, v reflect.Value, param string) bool {
+		maxLen, _ := strconv.Atoi(param)
+		return v.Kind() == reflect.String && helpers.IsValidPassword(v.String(), maxLen)
+	},
+	"min": func(_, v reflect.Value, param string) bool {
+		return helpers.IsGreaterThanOrEqualFloat(valueSize(v), ruleNumber(param))
+	},
+	"max": func(_, v reflect.Value, param string) bool {
+		return helpers.IsLessThanOrEqualFloat(valueSize(v), ruleNumber(param))
+	},
+	"len": func(_, v reflect.Value, param string) bool {
+		return valueSize(v) == ruleNumber(param)
+	},
+	"gt": func(_, v reflect.Value, param string) bool {
+		return helpers.IsGreaterThanFloat(valueSize(v), ruleNumber(param))
+	},
+	"gte": func(_, v reflect.Value, param string) bool {
+		return helpers.IsGreaterThanOrEqualFloat(valueSize(v), ruleNumber(param))
+	},
+	"lt": func(_, v reflect.Value, param string) bool {
+		return helpers.IsLessThanFloat(valueSize(v), ruleNumber(param))
+	},
+	"lte": func(_, v reflect.Value, param string) bool {
+		return helpers.IsLessThanOrEqualFloat(valueSize(v), ruleNumber(param))
+	},
+	"oneof": func(_, v reflect.Value, param string) bool {
+		s := fmt.Sprint(v.Interface())
+		for _, option := range strings.Fields(param) {
+			if s == option {
+				return true
+			}
+		}
+		return false
+	},
+	"eqfield": func(parent, v reflect.Value, param string) bool {
+		return fieldsEqual(v, parent.FieldByName(param))
+	},
+	"nefield": func(parent, v reflect.Value, param string) bool {
+		return !fieldsEqual(v, parent.FieldByName(param))
+	},
+}
+
+var (
+	// customRulesMu guards customRules.
+	customRulesMu sync.RWMutex
+	// customRules holds the rules registered with RegisterValidation, keyed by name.
+	customRules = make(map[string]customRule)
+	// validateCache holds the validateFields of the struct types already validated, keyed by their reflect.Type.
+	validateCache sync.Map
+)
+
+// RegisterValidation registers a custom validation rule, usable in validate tags under the given name.
+// The message is used in the field error when the rule fails, such as "must be a valid slug".
+// Rules are typically registered at startup, before the server handles requests:
+//
+//	context.RegisterValidation("slug", "must be a valid slug", func(value any, _ string) bool {
+//		s, ok := value.(string)
+//		return ok && slugPattern.MatchString(s)
+//	})
+//
+// It panics if the name is empty or is the name of a built-in rule.
+func RegisterValidation(name, message string, fn ValidationFunc) {
+	if _, ok := builtinRules[name]; ok || name == "" || name == "omitempty" || name == "dive" {
+		panic(fmt.Sprintf("validate: cannot register the rule %q", name))
+	}
+	customRulesMu.Lock()
+	defer customRulesMu.Unlock()
+	customRules[name] = customRule{fn: fn, message: message}
+}
+
+// Validate checks the struct pointed to by obj, or the struct itself, against the validate tags of its fields:
+//
+//	type SignUp struct {
+//		Email    string            `json:"email" validate:"required,email"`
+//		Username string            `json:"username" validate:"required,min=3,max=64"`
+//		Password string            `json:"password" validate:"required,password"`
+//		Confirm  string            `json:"confirm" validate:"eqfield=Password"`
+//		Plan     string            `json:"plan" validate:"oneof=free pro"`
+//		Tags     []string          `json:"tags" validate:"max=5,dive,min=2"`
+//		Address  Address           `json:"address"`
+//		Labels   map[string]string `json:"labels" validate:"dive,max=32"`
+//	}
+//
+// Rules are separated by commas, and run in order until one fails:
+// required, email, url, password (with an optional maximum length), min, max and len (the length of strings,
+// slices and maps, or the value of numbers), gt, gte, lt and lte, oneof (space-separated options),
+// eqfield and nefield (comparing with another field of the same struct), and the rules registered with
+// RegisterValidation. The omitempty rule skips the following rules when the field is empty,
+// and the rules given after dive apply to each element of a slice or map.
+// Nested structs, and the structs held by slices and maps, are validated recursively.
+// The errors of all the fields are returned together as ValidationErrors, rendered as a 422 Unprocessable Entity
+// response by the DefaultErrorHandler, with the field names taken from their json or binding tags,
+// such as "address.city" or "items[2].name".
+// A tag using an unknown rule, or a rule with an invalid parameter, is a programming error reported as a plain error,
+// rendered as a 500 Internal Server Error response by the DefaultErrorHandler.
+// MustValidateType reports these errors at startup instead.
+func Validate(obj any) error {
+	v := derefValue(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: expected a struct or a pointer to a struct, got %T", obj)
+	}
+
+	var errs ValidationErrors
+	if err := validateStruct(v, "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// MustValidateType checks the validate tags of the struct type of obj, and of the struct types it holds,
+// so that unknown rules and invalid parameters are reported at startup rather than on the first request.
+// It is typically called after the custom rules are registered, for each request type:
+//
+//	context.RegisterValidation("slug", "must be a valid slug", isSlug)
+//	context.MustValidateType(CreatePost{})
+//
+// It panics if a tag uses an unknown rule or a rule with an invalid parameter.
+func MustValidateType(obj any) {
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: expected a struct or a pointer to a struct, got %T", obj))
+	}
+	if err := checkValidateType(t, make(map[reflect.Type]bool)); err != nil {
+		panic(err.Error())
+	}
+}
+
+// checkValidateType checks the validate tags of the struct type and of the struct types held by its fields,
+// skipping the types already seen.
+func checkValidateType(t reflect.Type, seen map[reflect.Type]bool) error {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	fields, err := cachedValidateFields(t)
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		ft := t.Field(f.index).Type
+		for ft.Kind() == reflect.Pointer || ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array || ft.Kind() == reflect.Map {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			if err := checkValidateType(ft, seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks the struct pointed to by obj against the validate tags of its fields, see the Validate function.
+func (c *Context) Validate(obj any) error {
+	return Validate(obj)
+}
+
+// validateStruct checks the fields of the struct, whose names are prefixed with the given path.
+// It returns an error if the validate tags of the struct, or of a nested struct, are invalid.
+func validateStruct(v reflect.Value, prefix string, errs *ValidationErrors) error {
+	fields, err := cachedValidateFields(v.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		fv := v.Field(f.index)
+		path := prefix
+		if !f.embedded {
+			path = joinFieldPath(prefix, f.name)
+		}
+
+		if !checkRules(v, fv, path, f.rules, errs) {
+			continue
+		}
+
+		fv = derefValue(fv)
+		if !f.hasDive {
+			if err := validateNested(fv, path, errs); err != nil {
+				return err
+			}
+			continue
+		}
+		switch fv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < fv.Len(); i++ {
+				elemPath := fmt.Sprintf("%s[%d]", path, i)
+				if checkRules(v, fv.Index(i), elemPath, f.dive, errs) {
+					if err := validateNested(derefValue(fv.Index(i)), elemPath, errs); err != nil {
+						return err
+					}
+				}
+			}
+		case reflect.Map:
+			iter := fv.MapRange()
+			for iter.Next() {
+				elemPath := fmt.Sprintf("%s[%v]", path, iter.Key().Interface())
+				if checkRules(v, iter.Value(), elemPath, f.dive, errs) {
+					if err := validateNested(derefValue(iter.Value()), elemPath, errs); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateNested validates the value when it is a struct, or the structs held by a slice, array or map.
+func validateNested(v reflect.Value, path string, errs *ValidationErrors) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() != timeType {
+			return validateStruct(v, path, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if elem := derefValue(v.Index(i)); elem.Kind() == reflect.Struct {
+				if err := validateNested(elem, fmt.Sprintf("%s[%d]", path, i), errs); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if elem := derefValue(iter.Value()); elem.Kind() == reflect.Struct {
+				if err := validateNested(elem, fmt.Sprintf("%s[%v]", path, iter.Key().Interface()), errs); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkRules runs the rules against the value of a field, stopping at the first failing rule,
+// which is added to errs. It reports whether the value passed every rule and should be checked further:
+// it returns false for empty values when omitempty is given, and for nil pointers.
+func checkRules(parent, v reflect.Value, path string, rules []validationRule, errs *ValidationErrors) bool {
+	for _, rule := range rules {
+		switch rule.name {
+		case "omitempty":
+			if isEmptyValue(v) {
+				return false
+			}
+			continue
+		case "required":
+			if isEmptyValue(v) {
+				*errs = append(*errs, FieldError{Field: path, Rule: rule.name, Message: ruleMessage(rule, v)})
+				return false
+			}
+			continue
+		}
+
+		value := derefValue(v)
+		if !value.IsValid() {
+			return false
+		}
+
+		var ok bool
+		message := ""
+		if fn, builtin := builtinRules[rule.name]; builtin {
+			ok = fn(parent, value, rule.param)
+		} else {
+			customRulesMu.RLock()
+			custom := customRules[rule.name]
+			customRulesMu.RUnlock()
+			ok = custom.fn(value.Interface(), rule.param)
+			message = custom.message
+		}
+		if !ok {
+			if message == "" {
+				message = ruleMessage(rule, value)
+			}
+			*errs = append(*errs, FieldError{Field: path, Rule: rule.name, Message: message})
+			return false
+		}
+	}
+	return derefValue(v).IsValid()
+}
+
+// cachedValidateFields returns the validateFields of the struct type, computing them on first use.
+// Invalid tags are not cached, so that a rule registered later with RegisterValidation is taken into account.
+func cachedValidateFields(t reflect.Type) ([]validateField, error) {
+	if fields, ok := validateCache.Load(t); ok {
+		return fields.([]validateField), nil
+	}
+	fields, err := validateFields(t)
+	if err != nil {
+		return nil, err
+	}
+	cached, _ := validateCache.LoadOrStore(t, fields)
+	return cached.([]validateField), nil
+}
+
+// validateFields lists the fields of the struct type that have rules or may hold nested structs.
+// It returns an error if a tag uses an unknown rule or a rule with an invalid parameter.
+func validateFields(t reflect.Type) ([]validateField, error) {
+	var fields []validateField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("validate")
+		if tag == "-" {
+			continue
+		}
+
+		f := validateField{index: i, name: fieldDisplayName(sf), embedded: sf.Anonymous}
+		if tag != "" {
+			for _, part := range strings.Split(tag, ",") {
+				name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+				if name == "dive" {
+					f.hasDive = true
+					continue
+				}
+				if err := checkRuleDefinition(t, sf, name, param); err != nil {
+					return nil, err
+				}
+				if f.hasDive {
+					f.dive = append(f.dive, validationRule{name, param})
+				} else {
+					f.rules = append(f.rules, validationRule{name, param})
+				}
+			}
+		}
+
+		if tag != "" || mayHoldStructs(sf.Type) {
+			fields = append(fields, f)
+		}
+	}
+	return fields, nil
+}
+
+// checkRuleDefinition returns an error if the rule of the field is unknown or if its parameter is invalid.
+func checkRuleDefinition(t reflect.Type, sf reflect.StructField, name, param string) error {
+	reason := ""
+	switch name {
+	case "omitempty", "required", "email", "url", "oneof":
+	case "password":
+		if _, err := strconv.Atoi(param); param != "" && err != nil {
+			reason = "the maximum length must be an integer"
+		}
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			reason = "the parameter must be a number"
+		}
+	case "eqfield", "nefield":
+		if _, ok := t.FieldByName(param); !ok {
+			reason = "no such field " + param
+		}
+	default:
+		customRulesMu.RLock()
+		_, ok := customRules[name]
+		customRulesMu.RUnlock()
+		if !ok {
+			reason = "unknown rule"
+		}
+	}
+	if reason != "" {
+		return fmt.Errorf("validate: invalid rule %q on field %s.%s: %s", name, t.Name(), sf.Name, reason)
+	}
+	return nil
+}
+
+// mayHoldStructs reports whether values of the type can hold structs to validate recursively.
+func mayHoldStructs(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+// fieldDisplayName returns the name of the field in the error messages: the name of its json tag,
+// or of its binding tag, or its Go name.
+func fieldDisplayName(sf reflect.StructField) string {
+	for _, key := range append([]string{"json"}, bindSources...) {
+		if tag, ok := sf.Tag.Lookup(key); ok {
+			if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+				return name
+			}
+		}
+	}
+	return sf.Name
+}
+
+// joinFieldPath appends the field name to the path of its parent struct.
+func joinFieldPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// derefValue follows the pointers and interfaces of the value, and returns the invalid zero Value for nil ones.
+func derefValue(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// fieldsEqual reports whether the value of a field is equal to the value of another field, for eqfield and nefield.
+func fieldsEqual(v, other reflect.Value) bool {
+	other = derefValue(other)
+	return other.IsValid() && reflect.DeepEqual(v.Interface(), other.Interface())
+}
+
+// isEmptyValue reports whether the value is missing: a nil pointer, an empty string, slice or map, or a zero value.
+func isEmptyValue(v reflect.Value) bool {
+	v = derefValue(v)
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// valueSize returns the size compared by the min, max, len and comparison rules:
+// the number of characters of strings, the length of slices and maps, and the value of numbers.
+func valueSize(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String()))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return 0
+}
+
+// ruleNumber parses the numeric parameter of a rule, already checked when the struct type was first validated.
+func ruleNumber(param string) float64 {
+	n, _ := strconv.ParseFloat(param, 64)
+	return n
+}
+
+// ruleMessage returns the error message of a failed built-in rule, depending on the kind of the value.
+func ruleMessage(rule validationRule, v reflect.Value) string {
+	unit := ""
+	switch v.Kind() {
+	case reflect.String:
+		unit = " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	switch rule.name {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "password":
+		return "must be at least 8 characters long and contain an uppercase letter, a lowercase letter, a digit and a special character"
+	case "min":
+		if unit == " items" {
+			return "must contain at least " + rule.param + unit
+		}
+		return "must be at least " + rule.param + unit
+	case "max":
+		if unit == " items" {
+			return "must contain at most " + rule.param + unit
+		}
+		return "must be at most " + rule.param + unit
+	case "len":
+		if unit == " items" {
+			return "must contain exactly " + rule.param + unit
+		}
+		return "must be exactly " + rule.param + unit
+	case "gt":
+		return "must be greater than " + rule.param
+	case "gte":
+		return "must be greater than or equal to " + rule.param
+	case "lt":
+		return "must be less than " + rule.param
+	case "lte":
+		return "must be less than or equal to " + rule.param
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(rule.param), ", ")
+	case "eqfield":
+		return "must be equal to " + rule.param
+	case "nefield":
+		return "must be different from " + rule.param
+	}
+	return "is invalid"
+}
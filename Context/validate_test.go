@@ -0,0 +1,72 @@
+package Context
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type slugPost struct {
+	Slug string `json:"slug" validate:"required,testslug"`
+}
+
+type slugBlog struct {
+	Posts []slugPost `json:"posts"`
+}
+
+type badParam struct {
+	Name string `json:"name" validate:"min=abc"`
+}
+
+// unregisterValidation removes a rule registered with RegisterValidation, along with the cached validation
+// of every struct type, which may have been checked against the rule.
+func unregisterValidation(name string) {
+	customRulesMu.Lock()
+	defer customRulesMu.Unlock()
+	delete(customRules, name)
+	validateCache.Clear()
+}
+
+func TestValidateUnknownRule(t *testing.T) {
+	err := Validate(slugBlog{Posts: []slugPost{{Slug: "a"}}})
+	var errs ValidationErrors
+	if err == nil || errors.As(err, &errs) || !strings.Contains(err.Error(), `invalid rule "testslug"`) {
+		t.Fatalf("expected an invalid rule error, got %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"slug": "a"}`)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	DefaultErrorHandler(c, c.Bind(&slugPost{}))
+	c.Writer.WriteHeaderNow()
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected a 500 response, got %d", w.Code)
+	}
+
+	// The rule is taken into account once registered, although the type was already validated.
+	t.Cleanup(func() { unregisterValidation("testslug") })
+	RegisterValidation("testslug", "must be a valid slug", func(value any, _ string) bool {
+		s, ok := value.(string)
+		return ok && !strings.Contains(s, " ")
+	})
+	MustValidateType(&slugBlog{})
+	err = Validate(slugBlog{Posts: []slugPost{{Slug: "a b"}}})
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "posts[0].slug" || errs[0].Message != "must be a valid slug" {
+		t.Errorf("expected the slug rule to fail, got %v", err)
+	}
+}
+
+func TestMustValidateType(t *testing.T) {
+	for _, obj := range []any{badParam{}, &struct{ Nested []*badParam }{}, 42} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected MustValidateType(%T) to panic", obj)
+				}
+			}()
+			MustValidateType(obj)
+		}()
+	}
+}
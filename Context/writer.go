@@ -0,0 +1,145 @@
+package Context
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter is the http.ResponseWriter of a Context.
+// It records the status code, the number of body bytes written and whether the headers were sent,
+// so that middlewares such as loggers can inspect the response once the handler returns.
+// The status code is only sent with the first write to the body, with Flush or with WriteHeaderNow,
+// so that the status and headers can still be rewritten until then.
+// It also exposes the http.Flusher, http.Hijacker and http.Pusher interfaces of the underlying writer,
+// which report an error, or do nothing for Flush, when the underlying writer does not support them.
+type ResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+	http.Hijacker
+	http.Pusher
+
+	// Status returns the status code of the response, 200 if none was set.
+	Status() int
+	// Size returns the number of bytes written to the body of the response.
+	Size() int
+	// Written reports whether the status code and headers were sent to the client.
+	Written() bool
+	// WriteHeaderNow sends the status code and headers, if they were not sent yet.
+	WriteHeaderNow()
+	// Unwrap returns the underlying http.ResponseWriter, for use with http.ResponseController.
+	Unwrap() http.ResponseWriter
+}
+
+// responseWriter is the implementation of ResponseWriter wrapping an http.ResponseWriter.
+// It is stored by value in the Context, so that pooled contexts do not allocate it for each request.
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	size    int
+	written bool
+}
+
+var _ ResponseWriter = (*responseWriter)(nil)
+
+// NewResponseWriter wraps the given http.ResponseWriter into a ResponseWriter.
+// It is used when a Context is given a writer that does not record the status, for example by a net/http middleware.
+func NewResponseWriter(w http.ResponseWriter) ResponseWriter {
+	rw := new(responseWriter)
+	rw.reset(w)
+	return rw
+}
+
+// reset prepares the writer for a new response written to w.
+func (w *responseWriter) reset(rw http.ResponseWriter) {
+	w.ResponseWriter = rw
+	w.status = http.StatusOK
+	w.size = 0
+	w.written = false
+}
+
+// WriteHeader sets the status code of the response, which is sent with the first write to the body.
+// Informational 1xx status codes, such as 103 Early Hints, are sent immediately.
+// The status code cannot be changed once the headers were sent.
+func (w *responseWriter) WriteHeader(code int) {
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if !w.written {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow sends the status code and headers, if they were not sent yet.
+func (w *responseWriter) WriteHeaderNow() {
+	if !w.written {
+		w.written = true
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+}
+
+// Write sends the status code and headers if needed, then writes the data to the body of the response.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeaderNow()
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// WriteString writes the string to the body of the response, without converting it to a byte slice
+// when the underlying writer implements io.StringWriter.
+func (w *responseWriter) WriteString(s string) (int, error) {
+	w.WriteHeaderNow()
+	n, err := io.WriteString(w.ResponseWriter, s)
+	w.size += n
+	return n, err
+}
+
+// Status returns the status code of the response, 200 if none was set.
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+// Size returns the number of bytes written to the body of the response.
+func (w *responseWriter) Size() int {
+	return w.size
+}
+
+// Written reports whether the status code and headers were sent to the client.
+func (w *responseWriter) Written() bool {
+	return w.written
+}
+
+// Flush sends the status code and headers if needed, then flushes the buffered data to the client.
+// It does nothing if the underlying writer does not support flushing.
+func (w *responseWriter) Flush() {
+	w.WriteHeaderNow()
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack lets the caller take over the connection, for example to upgrade it to a WebSocket.
+// The response is considered written once the connection is hijacked.
+// It returns an error if the underlying writer does not support hijacking.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.written = true
+	}
+	return conn, rw, err
+}
+
+// Push initiates an HTTP/2 server push of the target resource.
+// It returns http.ErrNotSupported if the underlying writer does not support server push.
+func (w *responseWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Unwrap returns the underlying http.ResponseWriter, for use with http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
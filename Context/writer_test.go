@@ -0,0 +1,72 @@
+package Context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseWriterStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(c *Context)
+		status  int
+		size    int
+	}{
+		{"nothing written", func(c *Context) {}, http.StatusOK, 0},
+		{"write without WriteHeader", func(c *Context) { c.Writer.Write([]byte("hello")) }, http.StatusOK, 5},
+		{"WriteHeader on the writer", func(c *Context) {
+			c.Writer.WriteHeader(http.StatusAccepted)
+			c.Writer.Write([]byte("ok"))
+		}, http.StatusAccepted, 2},
+		{"status changed before the first write", func(c *Context) {
+			c.SetStatus(http.StatusCreated)
+			c.SetStatus(http.StatusConflict)
+			c.Writer.Write([]byte("x"))
+		}, http.StatusConflict, 1},
+		{"status changed after the first write", func(c *Context) {
+			c.Writer.Write([]byte("x"))
+			c.SetStatus(http.StatusInternalServerError)
+		}, http.StatusOK, 1},
+		{"status without body", func(c *Context) { c.SetStatus(http.StatusNoContent) }, http.StatusNoContent, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c := NewContext(w, httptest.NewRequest("GET", "/", nil))
+			tt.handler(c)
+			c.Writer.WriteHeaderNow()
+
+			if got := c.GetStatus(); got != tt.status {
+				t.Errorf("expected GetStatus %d, got %d", tt.status, got)
+			}
+			if w.Code != tt.status {
+				t.Errorf("expected the status %d to be sent, got %d", tt.status, w.Code)
+			}
+			if got := c.Writer.Size(); got != tt.size || w.Body.Len() != tt.size {
+				t.Errorf("expected a size of %d, got %d and a body of %d bytes", tt.size, got, w.Body.Len())
+			}
+			if !c.Written() {
+				t.Error("expected the response to be written")
+			}
+		})
+	}
+}
+
+func TestResponseWriterDefersHeader(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest("GET", "/", nil))
+	c.SetStatus(http.StatusNotFound)
+	c.Writer.Header().Set("X-Late", "1")
+	if c.Written() || w.Flushed || len(w.Header()) != 1 {
+		t.Fatal("expected nothing to be sent before the first write")
+	}
+
+	c.Writer.Flush()
+	if !c.Written() || w.Code != http.StatusNotFound || !w.Flushed {
+		t.Errorf("expected Flush to send the status, got %d", w.Code)
+	}
+	if c.Writer.Unwrap() != w {
+		t.Error("expected Unwrap to return the underlying writer")
+	}
+}
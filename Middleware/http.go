@@ -0,0 +1,37 @@
+package Middleware
+
+import (
+	"net/http"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+)
+
+// HTTPMiddleware adapts a standard net/http middleware to a Middleware.
+// It allows reusing the middlewares of the Go ecosystem, which have the func(http.Handler) http.Handler signature,
+// such as compression, rate limiting or tracing middlewares.
+// The writer and request passed by the net/http middleware to its next handler replace the ones of the context,
+// so that wrapped response writers and requests enriched with context values are seen by the rest of the chain.
+// The writer is wrapped into a context.ResponseWriter if needed, and the status it recorded is sent
+// before the net/http middleware regains control; the writer of the context is restored afterwards,
+// so that the middlewares running before this one see the status of the response.
+// Usage example:
+//
+//	r.Use(middleware.HTTPMiddleware(handlers.CompressHandler))
+func HTTPMiddleware(mw func(http.Handler) http.Handler) Middleware {
+	return func(next context.HandlerFunc) context.HandlerFunc {
+		return func(c *context.Context) {
+			writer := c.Writer
+			mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				rw, ok := w.(context.ResponseWriter)
+				if !ok {
+					rw = context.NewResponseWriter(w)
+				}
+				c.Writer = rw
+				c.Request = r
+				next(c)
+				rw.WriteHeaderNow()
+			})).ServeHTTP(writer, c.Request)
+			c.Writer = writer
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package Middleware
+
+import (
+	stdcontext "context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+)
+
+// userKey is the key of the request context value set by the net/http middleware of the tests.
+type userKey struct{}
+
+// statusRecorder is a response writer wrapper recording the status code, as net/http logging middlewares do.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	var seenStatus int
+	mw := HTTPMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Wrapped", "1")
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(stdcontext.WithValue(r.Context(), userKey{}, "ada")))
+			seenStatus = rec.status
+		})
+	})
+
+	var outerStatus int
+	outer := func(next context.HandlerFunc) context.HandlerFunc {
+		return func(c *context.Context) {
+			writer := c.Writer
+			next(c)
+			if c.Writer != writer {
+				t.Error("expected the writer of the context to be restored")
+			}
+			outerStatus = c.GetStatus()
+		}
+	}
+	handler := outer(mw(func(c *context.Context) {
+		if user, _ := c.Request.Context().Value(userKey{}).(string); user != "ada" {
+			t.Errorf("expected the request of the net/http middleware, got user %q", user)
+		}
+		if _, ok := c.Writer.Unwrap().(*statusRecorder); !ok {
+			t.Errorf("expected the writer of the net/http middleware, got %T", c.Writer.Unwrap())
+		}
+		c.SetStatus(http.StatusCreated)
+		c.Writer.Write([]byte("created"))
+	}))
+
+	w := httptest.NewRecorder()
+	c := context.NewContext(w, httptest.NewRequest("POST", "/users", nil))
+	handler(c)
+
+	if w.Code != http.StatusCreated || w.Body.String() != "created" || w.Header().Get("X-Wrapped") != "1" {
+		t.Errorf("unexpected response %d %q %v", w.Code, w.Body, w.Header())
+	}
+	if seenStatus != http.StatusCreated {
+		t.Errorf("expected the net/http middleware to see the status, got %d", seenStatus)
+	}
+	if outerStatus != http.StatusCreated {
+		t.Errorf("expected the outer middleware to see the status, got %d", outerStatus)
+	}
+}
+
+func TestHTTPMiddlewareShortCircuit(t *testing.T) {
+	mw := HTTPMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+		})
+	})
+	called := false
+	handler := mw(func(c *context.Context) { called = true })
+
+	w := httptest.NewRecorder()
+	c := context.NewContext(w, httptest.NewRequest("GET", "/", nil))
+	handler(c)
+	if called || w.Code != http.StatusTooManyRequests || c.GetStatus() != http.StatusTooManyRequests {
+		t.Errorf("expected the net/http middleware to answer, got %d (status %d), handler called: %v", w.Code, c.GetStatus(), called)
+	}
+}
@@ -7,7 +7,8 @@ import (
 // JWTAuthMiddleware is a middleware that validates JWT tokens in the Authorization header.
 // It checks if the token is present, validates it, and extracts the claims.
 // If the token is valid, it stores the claims in the context for further use.
-// If the token is missing or invalid, it responds with an unauthorized error.
+// If the token is missing or invalid, it responds with an unauthorized error and aborts the chain,
+// so that the handler and the remaining middlewares are skipped and IsAborted reports true to the middlewares before it.
 // The `dataKeyName` parameter specifies the key under which the claims will be stored in the context.
 // This middleware is useful for protecting routes that require authentication and authorization.
 // It ensures that only requests with valid JWT tokens can access the protected resources.
@@ -2,7 +2,6 @@ package Middleware
 
 import (
 	"log"
-	"net/http"
 	"time"
 
 	context "github.com/ines-mgg/LetsGoBack/Context"
@@ -10,6 +9,7 @@ import (
 
 // LoggerMiddleware is a middleware that logs the request and response details.
 // It logs the request method, path, status code, duration, and request ID.
+// The status code is the one actually sent by the handler, as recorded by the writer of the context.
 // The log format is customizable through the timeFormat parameter.
 // The middleware captures the start time of the request, calls the next handler,
 // and then logs the details after the handler has completed.
@@ -37,15 +37,9 @@ func LoggerMiddleware(timeFormat string) Middleware {
 			method := c.Method
 			path := c.Path
 
-			reqID, _ := c.Get("request_id")
-			reqIDStr, _ := reqID.(string)
-
-			if status == 0 {
-				status = http.StatusOK
-			}
-
-			if reqID == "" {
-				reqID = "n/a"
+			reqIDStr := c.RequestID()
+			if reqIDStr == "" {
+				reqIDStr = "n/a"
 			}
 
 			log.Printf("%s [%s] [%s] %s - %d (%s) - reqID: %s",
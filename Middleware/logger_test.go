@@ -0,0 +1,42 @@
+package Middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+)
+
+func TestLoggerLogsSentStatus(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	tests := []struct {
+		name    string
+		handler context.HandlerFunc
+		logged  string
+	}{
+		{"write without status", func(c *context.Context) { c.Writer.Write([]byte("ok")) }, "[INFO]"},
+		{"error helper", func(c *context.Context) { c.ErrorUnauthorized("Missing token") }, "- 401 ("},
+		{"abort with status", func(c *context.Context) { c.AbortWithStatus(http.StatusTooManyRequests) }, "- 429 ("},
+		{"second response ignored", func(c *context.Context) {
+			c.ErrorNotFound("Unknown user")
+			c.RespondOK("ok")
+		}, "- 404 ("},
+		{"status set on the writer", func(c *context.Context) { c.Writer.WriteHeader(http.StatusBadGateway) }, "[ERROR]"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		c := context.NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/users", nil))
+		LoggerMiddleware("15:04:05")(tt.handler)(c)
+		if !strings.Contains(buf.String(), tt.logged) {
+			t.Errorf("%s: expected the log to contain %q, got %q", tt.name, tt.logged, buf.String())
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package Router
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// constraint restricts the values accepted by a ":name" path parameter.
+// It is declared in the route pattern between angle brackets, right after the parameter name,
+// for example "/users/:id<int>", "/posts/:slug<uuid>" or "/files/:name<[a-z0-9-]+>".
+// The expr field holds the raw text between the brackets and the match function validates a segment value.
+// When the value of a segment does not satisfy the constraint, the parameter does not match
+// and the router falls through to the other routes, or to the not found handler.
+type constraint struct {
+	expr  string
+	match func(string) bool
+}
+
+// paramTypes maps the names of the built-in constraint types to their validation function.
+// Any constraint expression that is not one of these names is compiled as a regular expression.
+var paramTypes = map[string]func(string) bool{
+	"int": func(s string) bool {
+		_, err := strconv.Atoi(s)
+		return err == nil
+	},
+	"uint": func(s string) bool {
+		_, err := strconv.ParseUint(s, 10, 0)
+		return err == nil
+	},
+	"float": func(s string) bool {
+		_, err := strconv.ParseFloat(s, 64)
+		return err == nil
+	},
+	"bool": func(s string) bool {
+		_, err := strconv.ParseBool(s)
+		return err == nil
+	},
+	"uuid": func(s string) bool {
+		if len(s) != 36 {
+			return false
+		}
+		_, err := uuid.Parse(s)
+		return err == nil
+	},
+	"alpha": func(s string) bool {
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+				return false
+			}
+		}
+		return true
+	},
+	"alnum": func(s string) bool {
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+				return false
+			}
+		}
+		return true
+	},
+}
+
+// newConstraint builds the constraint for the given expression.
+// Built-in type names such as "int" or "uuid" use their dedicated validation function,
+// any other expression is compiled as a regular expression anchored on the whole segment.
+// It returns an error if the expression is not a valid regular expression.
+func newConstraint(expr string) (*constraint, error) {
+	if match, ok := paramTypes[expr]; ok {
+		return &constraint{expr: expr, match: match}, nil
+	}
+
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraint %q: %v", expr, err)
+	}
+	return &constraint{expr: expr, match: re.MatchString}, nil
+}
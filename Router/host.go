@@ -0,0 +1,123 @@
+package Router
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// hostRoutes holds the routes registered for a host pattern, such as ":tenant.api.example.com".
+// The pattern is split into its dot-separated labels, where a label starting with ":" is a parameter
+// matching exactly one label of the request host.
+// The dynamic field reports whether the pattern contains at least one parameter.
+// The trees field holds one routing tree per HTTP method, like the default trees of the Router.
+type hostRoutes struct {
+	pattern string
+	labels  []string
+	dynamic bool
+	trees   map[string]*node
+}
+
+// newHostRoutes parses the host pattern and returns an empty set of routes for it.
+// It returns an error if the pattern is empty or if one of its labels is malformed.
+func newHostRoutes(pattern string) (*hostRoutes, error) {
+	if pattern == "" {
+		return nil, errors.New("host pattern must not be empty")
+	}
+
+	h := &hostRoutes{
+		pattern: pattern,
+		labels:  strings.Split(pattern, "."),
+		trees:   make(map[string]*node),
+	}
+	for _, label := range h.labels {
+		switch {
+		case label == "":
+			return nil, fmt.Errorf("host pattern %q contains an empty label", pattern)
+		case label == ":":
+			return nil, fmt.Errorf("host pattern %q contains a parameter without name", pattern)
+		case label[0] == ':':
+			h.dynamic = true
+		case strings.ContainsAny(label, ":*/"):
+			return nil, fmt.Errorf("host pattern %q contains an invalid label %q", pattern, label)
+		}
+	}
+	return h, nil
+}
+
+// match reports whether the host matches the pattern, and returns the parameters captured from its labels.
+// Literal labels are compared case-insensitively, as host names are.
+// The params slice is only allocated when the pattern contains parameters.
+func (h *hostRoutes) match(host string) ([]param, bool) {
+	var ps []param
+	for i, label := range h.labels {
+		var part string
+		if i == len(h.labels)-1 {
+			part, host = host, ""
+		} else {
+			dot := strings.IndexByte(host, '.')
+			if dot < 0 {
+				return nil, false
+			}
+			part, host = host[:dot], host[dot+1:]
+		}
+
+		if label[0] == ':' {
+			if part == "" {
+				return nil, false
+			}
+			ps = append(ps, param{label[1:], part})
+		} else if !strings.EqualFold(label, part) {
+			return nil, false
+		}
+	}
+	return ps, true
+}
+
+// requestHost returns the host name of the request, without the port.
+func requestHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// Host creates a new route group whose routes only match requests for the given host pattern.
+// Labels prefixed with ":" are parameters, such as "tenant" in ":tenant.api.example.com",
+// and their values are merged into the Params of the Context along with the path parameters.
+// The port of the request host is ignored when matching.
+// Hosts without parameters are tried before hosts with parameters, then in registration order.
+// The routes registered directly on the router are the default host fallback: they are only used
+// when no host pattern matches the request host. Requests for a host matching a pattern only reach
+// the routes of the matching Host groups, so that routes such as admin endpoints registered on the router
+// are not exposed on every tenant host; they get a 404 or 405 response when none of these routes matches.
+// Calling Host twice with the same pattern returns groups sharing the same routes.
+// It panics if the pattern is malformed, or if the router is already compiled.
+func (r *Router) Host(pattern string) *routeGroup {
+	if r.compiled.Load() {
+		panic(fmt.Sprintf("router: cannot register host %s: hosts cannot be added once the router is compiled", pattern))
+	}
+	for _, h := range r.hosts {
+		if h.pattern == pattern {
+			return &routeGroup{router: r, host: h}
+		}
+	}
+
+	h, err := newHostRoutes(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("router: cannot register host: %v", err))
+	}
+
+	i := len(r.hosts)
+	if !h.dynamic {
+		for i > 0 && r.hosts[i-1].dynamic {
+			i--
+		}
+	}
+	r.hosts = append(r.hosts, nil)
+	copy(r.hosts[i+1:], r.hosts[i:])
+	r.hosts[i] = h
+
+	return &routeGroup{router: r, host: h}
+}
@@ -0,0 +1,42 @@
+package Router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+)
+
+func TestHostFallback(t *testing.T) {
+	r := NewRouter()
+	r.GET("/admin", func(c *context.Context) { c.RespondOK("admin") })
+	r.Host(":tenant.api.example.com").GET("/users", func(c *context.Context) { c.RespondOK(c.Param("tenant")) })
+	r.Host("api.example.com").GET("/status", func(c *context.Context) { c.RespondOK("status") })
+
+	tests := []struct {
+		host   string
+		method string
+		path   string
+		status int
+	}{
+		{"acme.api.example.com", "GET", "/users", http.StatusOK},
+		{"acme.api.example.com", "GET", "/admin", http.StatusNotFound},
+		{"acme.api.example.com:8080", "GET", "/admin", http.StatusNotFound},
+		{"acme.api.example.com", "POST", "/users", http.StatusMethodNotAllowed},
+		{"api.example.com", "GET", "/status", http.StatusOK},
+		{"api.example.com", "GET", "/admin", http.StatusNotFound},
+		{"internal.example.com", "GET", "/admin", http.StatusOK},
+		{"internal.example.com", "GET", "/users", http.StatusNotFound},
+		{"localhost", "GET", "/admin", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Host = tt.host
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s %s%s: expected %d, got %d", tt.method, tt.host, tt.path, tt.status, w.Code)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package Router
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// systemdFirstFD is the first file descriptor passed by systemd socket activation.
+const systemdFirstFD = 3
+
+// ServeWithConfig serves the router on an existing listener, with the timeouts and limits of the given configuration.
+// It is useful for listeners created by the caller, such as pre-opened or inherited sockets.
+// It returns nil once the server has been shut down gracefully, or the error that stopped it.
+func (r *Router) ServeWithConfig(l net.Listener, cfg ServerConfig) error {
+	srv := r.newServer(l.Addr().String(), cfg)
+	return r.serve(srv, cfg, func() error {
+		return srv.Serve(l)
+	})
+}
+
+// Serve serves the router on an existing listener with the default server configuration.
+// It returns nil once the server has been shut down gracefully, or the error that stopped it.
+func (r *Router) Serve(l net.Listener) error {
+	return r.ServeWithConfig(l, ServerConfig{})
+}
+
+// UnixListener creates a listener on the Unix domain socket at the given path.
+// A stale socket left at this path by a previous process is removed first,
+// and the socket file is given the mode, such as 0660 to restrict it to the owner and group.
+// The socket file is removed when the listener is closed.
+func UnixListener(path string, mode fs.FileMode) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// ListenUnix starts the HTTP server on the Unix domain socket at the given path, using the router as handler.
+// The socket file is created with the 0660 mode and removed when the server stops.
+// It returns nil once the server has been shut down gracefully, or the error that stopped it.
+func (r *Router) ListenUnix(path string) error {
+	l, err := UnixListener(path, 0660)
+	if err != nil {
+		return err
+	}
+	return r.Serve(l)
+}
+
+// SystemdListeners returns the listeners passed to the process by systemd socket activation.
+// It reads the LISTEN_PID and LISTEN_FDS environment variables set by systemd,
+// and returns no listener if they are not set or are meant for another process.
+// The listeners are returned in the order of the ListenStream entries of the socket unit.
+func SystemdListeners() ([]net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil, nil
+	}
+
+	listeners := make([]net.Listener, 0, count)
+	for fd := systemdFirstFD; fd < systemdFirstFD+count; fd++ {
+		l, err := FileListener(uintptr(fd), "systemd-fd-"+strconv.Itoa(fd))
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// FileListener creates a listener from an open file descriptor, such as a socket inherited from a parent process.
+// The name is only used in error messages.
+func FileListener(fd uintptr, name string) (net.Listener, error) {
+	f := os.NewFile(fd, name)
+	if f == nil {
+		return nil, fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	defer f.Close()
+
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return l, nil
+}
+
+// ServeListeners serves the router on several listeners at once, such as an internal port,
+// a public port and a Unix domain socket, with the same server configuration.
+// All the servers are stopped together: by Shutdown, on SIGINT or SIGTERM when cfg.HandleSignals is set,
+// or when one of them fails, in which case the others are shut down gracefully.
+// It returns once every server has stopped, with nil after a graceful shutdown or the errors that stopped them.
+// Usage example:
+//
+//	internal, _ := net.Listen("tcp", "127.0.0.1:9090")
+//	public, _ := net.Listen("tcp", ":8080")
+//	sidecar, _ := router.UnixListener("/run/app.sock", 0660)
+//	err := r.ServeListeners(router.ServerConfig{HandleSignals: true}, internal, public, sidecar)
+func (r *Router) ServeListeners(cfg ServerConfig, listeners ...net.Listener) error {
+	if len(listeners) == 0 {
+		return errors.New("at least one listener is required")
+	}
+
+	if cfg.HandleSignals {
+		stop := r.shutdownOnSignal(cfg.ShutdownTimeout)
+		defer stop()
+	}
+	serverCfg := cfg
+	serverCfg.HandleSignals = false
+
+	results := make(chan error, len(listeners))
+	for _, l := range listeners {
+		go func(l net.Listener) {
+			results <- r.ServeWithConfig(l, serverCfg)
+		}(l)
+	}
+
+	var errs []error
+	for range listeners {
+		err := <-results
+		if err == nil || errors.Is(err, http.ErrServerClosed) {
+			continue
+		}
+		errs = append(errs, err)
+		go r.shutdownWithin(cfg.ShutdownTimeout)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,177 @@
+//go:build unix
+
+package Router
+
+import (
+	stdcontext "context"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+)
+
+// unixClient returns an HTTP client sending every request to the Unix domain socket at the given path.
+func unixClient(path string) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx stdcontext.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+}
+
+// getBody sends a GET request with the client and returns the body of the response.
+func getBody(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestUnixListener(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+
+	// A stale socket left by a previous process is replaced.
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	l, err := UnixListener(path, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode()&fs.ModeSocket == 0 || info.Mode().Perm() != 0600 {
+		t.Fatalf("expected a socket with the 0600 mode, got %v, %v", info, err)
+	}
+
+	r := NewRouter()
+	r.GET("/ping", func(c *context.Context) { c.Writer.Write([]byte("pong")) })
+	done := make(chan error, 1)
+	go func() { done <- r.Serve(l) }()
+
+	if body := getBody(t, unixClient(path), "http://unix/ping"); body != "pong" {
+		t.Errorf("expected pong, got %q", body)
+	}
+
+	if err := r.Shutdown(stdcontext.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("expected a graceful shutdown, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the socket file to be removed, got %v", err)
+	}
+}
+
+func TestUnixListenerRefusesRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+	if err := os.WriteFile(path, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnixListener(path, 0600); err == nil {
+		t.Error("expected an error for a path that is not a socket")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "data" {
+		t.Error("expected the file to be kept")
+	}
+}
+
+func TestFileListener(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcp.Close()
+	f, err := tcp.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// FileListener takes ownership of the descriptor, as for one inherited from a parent process,
+	// so it is given a duplicate of the descriptor of the file, which is closed on its own.
+	fd, err := syscall.Dup(int(f.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := FileListener(uintptr(fd), "inherited")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRouter()
+	r.GET("/ping", func(c *context.Context) { c.Writer.Write([]byte("pong")) })
+	go r.Serve(l)
+	defer r.Shutdown(stdcontext.Background())
+
+	if body := getBody(t, http.DefaultClient, "http://"+tcp.Addr().String()+"/ping"); body != "pong" {
+		t.Errorf("expected pong, got %q", body)
+	}
+}
+
+func TestSystemdListenersIgnoresOtherProcesses(t *testing.T) {
+	tests := []struct {
+		pid string
+		fds string
+	}{
+		{"", ""},
+		{strconv.Itoa(os.Getpid() + 1), "1"},
+		{strconv.Itoa(os.Getpid()), "0"},
+		{strconv.Itoa(os.Getpid()), "abc"},
+	}
+	for _, tt := range tests {
+		t.Setenv("LISTEN_PID", tt.pid)
+		t.Setenv("LISTEN_FDS", tt.fds)
+		if listeners, err := SystemdListeners(); listeners != nil || err != nil {
+			t.Errorf("LISTEN_PID=%q LISTEN_FDS=%q: expected no listener, got %v, %v", tt.pid, tt.fds, listeners, err)
+		}
+	}
+}
+
+func TestServeListeners(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "app.sock")
+	unix, err := UnixListener(path, 0660)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRouter()
+	r.GET("/ping", func(c *context.Context) { c.Writer.Write([]byte("pong")) })
+	done := make(chan error, 1)
+	go func() { done <- r.ServeListeners(ServerConfig{}, tcp, unix) }()
+
+	if body := getBody(t, http.DefaultClient, "http://"+tcp.Addr().String()+"/ping"); body != "pong" {
+		t.Errorf("TCP: expected pong, got %q", body)
+	}
+	if body := getBody(t, unixClient(path), "http://unix/ping"); body != "pong" {
+		t.Errorf("Unix socket: expected pong, got %q", body)
+	}
+
+	if err := r.Shutdown(stdcontext.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("expected every server to stop gracefully, got %v", err)
+	}
+	if err := r.ServeListeners(ServerConfig{}); err == nil {
+		t.Error("expected an error without listener")
+	}
+}
@@ -0,0 +1,69 @@
+package Router
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+)
+
+func TestMount(t *testing.T) {
+	// echo writes the method, path and query seen by the mounted handler, and the parameters of the mount prefix.
+	echo := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%s %s?%s %v", req.Method, req.URL.Path, req.URL.RawQuery, context.RequestParams(req))
+	})
+
+	sub := NewRouter()
+	sub.GET("/projects/:project", func(c *context.Context) {
+		fmt.Fprintf(c.Writer, "org=%s project=%s", c.Param("org"), c.Param("project"))
+	})
+
+	r := NewRouter()
+	r.Mount("/admin", echo)
+	r.Mount("/orgs/:org/", sub)
+	r.Mount("/raw/:id", echo)
+	api := r.Group("/api")
+	api.Use(func(next context.HandlerFunc) context.HandlerFunc {
+		return func(c *context.Context) {
+			c.Writer.Header().Set("X-Group", "api")
+			next(c)
+		}
+	})
+	api.Mount("/legacy", echo)
+	r.GET("/admin/health", func(c *context.Context) { c.Writer.Write([]byte("router")) })
+
+	tests := []struct {
+		method string
+		target string
+		status int
+		body   string
+		group  bool
+	}{
+		{"GET", "/admin/users?page=2", http.StatusOK, "GET /users?page=2 map[]", false},
+		{"GET", "/admin", http.StatusOK, "GET /? map[]", false},
+		{"GET", "/admin/", http.StatusOK, "GET /? map[]", false},
+		{"DELETE", "/admin/users/1", http.StatusOK, "DELETE /users/1? map[]", false},
+		{"OPTIONS", "/admin/users", http.StatusOK, "OPTIONS /users? map[]", false},
+		// Routes registered on the router win over the mounted handler.
+		{"GET", "/admin/health", http.StatusOK, "router", false},
+		// A mounted router sees the parameters of the prefix along with its own.
+		{"GET", "/orgs/acme/projects/site", http.StatusOK, "org=acme project=site", false},
+		{"GET", "/orgs/acme/unknown", http.StatusNotFound, "404 page not found\n", false},
+		{"POST", "/raw/7/items", http.StatusOK, "POST /items? map[id:7]", false},
+		// Handlers mounted through a group run the group middlewares.
+		{"PUT", "/api/legacy/v1/users", http.StatusOK, "PUT /v1/users? map[]", true},
+		{"GET", "/other", http.StatusNotFound, "404 page not found\n", false},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+		if w.Code != tt.status || w.Body.String() != tt.body {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.method, tt.target, tt.status, tt.body, w.Code, w.Body)
+		}
+		if got := w.Header().Get("X-Group") == "api"; got != tt.group {
+			t.Errorf("%s %s: expected the group middleware to run: %v", tt.method, tt.target, tt.group)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package Router
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// cleanPath returns the canonical form of a URL path.
+// It collapses repeated slashes, resolves "." and ".." elements and makes sure the path begins with "/".
+// Unlike path.Clean, it keeps the trailing slash of the original path, so that "/a//b/../c/" becomes "/a/c/".
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	cleaned := path.Clean("/" + p)
+	if cleaned != "/" && strings.HasSuffix(p, "/") {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// toggleTrailingSlash adds a trailing slash to the path, or removes it if the path already has one.
+func toggleTrailingSlash(p string) string {
+	if strings.HasSuffix(p, "/") {
+		return p[:len(p)-1]
+	}
+	return p + "/"
+}
+
+// findFold resolves the remaining path below n like find, but compares static segments case-insensitively.
+// It appends the path of the matched route, with the case of its static segments, to out,
+// and reports whether a registered route was found.
+// Parameter values are kept as they appear in the request path.
+func (n *node) findFold(path string, out []byte) ([]byte, bool) {
+	if path == "" {
+		if n.route != nil || (n.catchAll != nil && n.catchAll.route != nil) {
+			return out, true
+		}
+		return nil, false
+	}
+
+	for _, child := range n.children {
+		if len(path) >= len(child.prefix) && strings.EqualFold(path[:len(child.prefix)], child.prefix) {
+			if fixed, ok := child.findFold(path[len(child.prefix):], append(out, child.prefix...)); ok {
+				return fixed, true
+			}
+		}
+	}
+
+	if len(n.params) > 0 {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if end > 0 {
+			value := path[:end]
+			for _, child := range n.params {
+				if child.constraint != nil && !child.constraint.match(value) {
+					continue
+				}
+				if fixed, ok := child.findFold(path[end:], append(out, value...)); ok {
+					return fixed, true
+				}
+			}
+		}
+	}
+
+	if n.catchAll != nil && n.catchAll.route != nil {
+		return append(out, path...), true
+	}
+
+	return nil, false
+}
+
+// lookupFold resolves the path case-insensitively among the routes of the given host and method,
+// trying the host routes first as lookup does, and returns the path of the matched route.
+// As with lookup, the routes registered directly on the router are only tried when no host pattern matches.
+func (r *Router) lookupFold(host, method, path string) (string, bool) {
+	matched := false
+	for _, h := range r.hosts {
+		if _, ok := h.match(host); !ok {
+			continue
+		}
+		matched = true
+		if root := h.trees[method]; root != nil {
+			if fixed, ok := root.findFold(path, nil); ok {
+				return string(fixed), true
+			}
+		}
+	}
+
+	if root := r.trees[method]; root != nil && !matched {
+		if fixed, ok := root.findFold(path, nil); ok {
+			return string(fixed), true
+		}
+	}
+	return "", false
+}
+
+// redirectPath returns the path a request should be redirected to when its path does not match any route,
+// according to the RedirectTrailingSlash and RedirectFixedPath options of the router.
+// HEAD requests are redirected when the fixed path matches a GET route, since they are served by GET handlers.
+// It reports false when no other path matches a route for the method.
+func (r *Router) redirectPath(host, method, p string) (string, bool) {
+	found := func(candidate string) bool {
+		if rt, _ := r.lookup(host, method, candidate); rt != nil {
+			return true
+		}
+		if method == http.MethodHead {
+			rt, _ := r.lookup(host, http.MethodGet, candidate)
+			return rt != nil
+		}
+		return false
+	}
+	fold := func(candidate string) (string, bool) {
+		if fixed, ok := r.lookupFold(host, method, candidate); ok {
+			return fixed, true
+		}
+		if method == http.MethodHead {
+			return r.lookupFold(host, http.MethodGet, candidate)
+		}
+		return "", false
+	}
+
+	if r.RedirectTrailingSlash && p != "/" {
+		if candidate := toggleTrailingSlash(p); found(candidate) {
+			return candidate, true
+		}
+	}
+
+	if r.RedirectFixedPath {
+		cleaned := cleanPath(p)
+		if fixed, ok := fold(cleaned); ok && fixed != p {
+			return fixed, true
+		}
+		if r.RedirectTrailingSlash && cleaned != "/" {
+			if fixed, ok := fold(toggleTrailingSlash(cleaned)); ok && fixed != p {
+				return fixed, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// redirect sends a permanent redirect to the given path, keeping the query string of the request.
+// GET and HEAD requests get a 301 Moved Permanently, while other methods get a 308 Permanent Redirect
+// so that clients repeat the request with the same method and body.
+// The raw parameter reports whether the path is already escaped, as when UseRawPath is set.
+func redirect(w http.ResponseWriter, req *http.Request, p string, raw bool) {
+	code := http.StatusMovedPermanently
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		code = http.StatusPermanentRedirect
+	}
+
+	location := p
+	if !raw {
+		location = (&url.URL{Path: p}).EscapedPath()
+	}
+	if req.URL.RawQuery != "" {
+		location += "?" + req.URL.RawQuery
+	}
+	http.Redirect(w, req, location, code)
+}
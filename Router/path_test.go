@@ -0,0 +1,142 @@
+package Router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+)
+
+func TestRedirects(t *testing.T) {
+	r := NewRouter()
+	r.RedirectFixedPath = true
+	r.GET("/users", noop)
+	r.POST("/users", noop)
+	r.GET("/posts/", noop)
+	r.GET("/Docs/:page", noop)
+	r.OPTIONS("/items", noop)
+
+	tests := []struct {
+		method   string
+		target   string
+		status   int
+		location string
+	}{
+		// Trailing slash redirects, with 301 for GET and HEAD and 308 for the other methods.
+		{"GET", "/users/", http.StatusMovedPermanently, "/users"},
+		{"HEAD", "/users/", http.StatusMovedPermanently, "/users"},
+		{"POST", "/users/", http.StatusPermanentRedirect, "/users"},
+		{"GET", "/posts", http.StatusMovedPermanently, "/posts/"},
+		{"GET", "/users/?page=2&sort=name", http.StatusMovedPermanently, "/users?page=2&sort=name"},
+		// Fixed path redirects, cleaning the path and fixing the case of static segments.
+		{"GET", "/USERS", http.StatusMovedPermanently, "/users"},
+		{"GET", "//users/../users", http.StatusMovedPermanently, "/users"},
+		{"GET", "/docs/Intro?v=1", http.StatusMovedPermanently, "/Docs/Intro?v=1"},
+		{"GET", "/DOCS/Intro/", http.StatusMovedPermanently, "/Docs/Intro"},
+		{"POST", "/Users", http.StatusPermanentRedirect, "/users"},
+		// Matching routes are served, and paths matching no route get a 404.
+		{"GET", "/users", http.StatusOK, ""},
+		{"GET", "/unknown/", http.StatusNotFound, ""},
+		// OPTIONS requests are only redirected to a dedicated OPTIONS route, as at /items.
+		{"OPTIONS", "/users/", http.StatusNotFound, ""},
+		{"OPTIONS", "/items/", http.StatusPermanentRedirect, "/items"},
+		// A path matching routes for other methods gets a 405 rather than a redirect.
+		{"PUT", "/users", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.target, tt.status, w.Code)
+		}
+		if got := w.Header().Get("Location"); got != tt.location {
+			t.Errorf("%s %s: expected Location %q, got %q", tt.method, tt.target, tt.location, got)
+		}
+	}
+}
+
+func TestRedirectsDisabled(t *testing.T) {
+	r := NewRouter()
+	r.RedirectTrailingSlash = false
+	r.GET("/users", noop)
+
+	for _, target := range []string{"/users/", "/USERS"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404 without redirects, got %d", target, w.Code)
+		}
+	}
+}
+
+func TestOptionsBeforeRedirect(t *testing.T) {
+	r := NewRouter()
+	r.GET("/users/", noop)
+	r.OPTIONS("/users", noop)
+	r.GET("/posts", noop)
+	r.OPTIONS("/posts", noop)
+
+	// The path matches the GET route: the automatic OPTIONS answer wins over the redirect to the OPTIONS route.
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("OPTIONS", "/users/", nil))
+	if w.Code != http.StatusNoContent || w.Header().Get("Allow") != "GET, HEAD, OPTIONS" {
+		t.Errorf("expected an automatic OPTIONS answer, got %d with Allow %q", w.Code, w.Header().Get("Allow"))
+	}
+
+	// CORS preflight requests are never redirected.
+	req := httptest.NewRequest("OPTIONS", "/posts/", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code == http.StatusPermanentRedirect || w.Header().Get("Location") != "" {
+		t.Errorf("expected the preflight not to be redirected, got %d to %q", w.Code, w.Header().Get("Location"))
+	}
+}
+
+func TestRawPath(t *testing.T) {
+	r := NewRouter()
+	r.UseRawPath = true
+	r.RedirectFixedPath = true
+	r.GET("/files/:name", func(c *context.Context) { c.Writer.Write([]byte(c.Param("name"))) })
+	r.GET("/dirs/:name/", noop)
+
+	tests := []struct {
+		target   string
+		status   int
+		body     string
+		location string
+	}{
+		// The escaped slash is matched as part of the parameter, which is unescaped.
+		{"/files/a%2Fb", http.StatusOK, "a/b", ""},
+		{"/files/a%20b", http.StatusOK, "a b", ""},
+		// Redirects keep the path escaped, and the query string.
+		{"/dirs/a%2Fb?x=1", http.StatusMovedPermanently, "", "/dirs/a%2Fb/?x=1"},
+		{"/FILES/a%2Fb", http.StatusMovedPermanently, "", "/files/a%2Fb"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.target, tt.status, w.Code)
+		}
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s: expected %q, got %q", tt.target, tt.body, w.Body)
+		}
+		if got := w.Header().Get("Location"); got != tt.location {
+			t.Errorf("%s: expected Location %q, got %q", tt.target, tt.location, got)
+		}
+	}
+
+	// Without UnescapePathValues, the parameter is kept escaped.
+	r2 := NewRouter()
+	r2.UseRawPath = true
+	r2.UnescapePathValues = false
+	r2.GET("/files/:name", func(c *context.Context) { c.Writer.Write([]byte(c.Param("name"))) })
+	w := httptest.NewRecorder()
+	r2.ServeHTTP(w, httptest.NewRequest("GET", "/files/a%2Fb", nil))
+	if w.Body.String() != "a%2Fb" {
+		t.Errorf("expected the parameter to be kept escaped, got %q", w.Body)
+	}
+}
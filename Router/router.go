@@ -1,7 +1,10 @@
 package Router
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
 
 	context "github.com/ines-mgg/LetsGoBack/Context"
@@ -9,74 +12,180 @@ import (
 )
 
 // NewRouter creates a new Router instance.
-// It initializes the Handlers map and the DynamicRoutes slice.
+// It initializes the map holding the routing tree of each HTTP method,
+// enables the automatic handling of 405 Method Not Allowed and OPTIONS responses,
+// the trailing slash redirects, and the unescaping of parameters captured from the escaped path.
 func NewRouter() *Router {
 	return &Router{
-		Handlers: make(map[string]map[string]context.HandlerFunc),
+		trees:                  make(map[string]*node),
+		names:                  make(map[string]*Route),
+		shutdownDone:           make(chan struct{}),
+		HandleMethodNotAllowed: true,
+		HandleOPTIONS:          true,
+		RedirectTrailingSlash:  true,
+		UnescapePathValues:     true,
 	}
 }
 
 // addRoute adds a new route to the router.
-// It checks if the path contains a dynamic segment (indicated by ":").
-// If it does, the route is added to the DynamicRoutes slice.
-func (r *Router) addRoute(method string, path string, handler context.HandlerFunc) {
-	if strings.Contains(path, ":") {
-		r.DynamicRoutes = append(r.DynamicRoutes, dynamicRoute{
-			method,
-			path,
-			handler,
-		})
-		return
-	}
-
-	if r.Handlers[method] == nil {
-		r.Handlers[method] = make(map[string]context.HandlerFunc)
-	}
-	r.Handlers[method][path] = handler
-}
-
-// handle is a helper function to add a dynamic route.
-func (r *Router) handle(method, path string, handler context.HandlerFunc) {
-	r.DynamicRoutes = append(r.DynamicRoutes, dynamicRoute{
-		method,
-		path,
-		handler,
-	})
+// The route is inserted in the routing tree of its method, which is created on first use,
+// among the trees of the host of its group if it was registered through a Host group.
+// Static, ":param" and "*catchall" segments are all handled by the tree.
+// The group is the route group the route is registered through, or nil for routes registered on the router,
+// and mws are the middlewares given for this route only.
+// The final handler chain of the route is built by Compile, once all the middlewares are known.
+// It panics if the router is already compiled, if the path is malformed, already registered for the method,
+// or ambiguous with an existing route, so that conflicts are detected at startup.
+// It returns the registered route, which can be named to build its URL later on.
+func (r *Router) addRoute(method string, path string, group *routeGroup, mws []middleware.Middleware, handler context.HandlerFunc) *Route {
+	if r.compiled.Load() {
+		panic(fmt.Sprintf("router: cannot register %s %s: routes cannot be added once the router is compiled", method, path))
+	}
+
+	trees := r.trees
+	if group != nil && group.host != nil {
+		trees = group.host.trees
+	}
+	root := trees[method]
+	if root == nil {
+		root = &node{kind: staticNode}
+		trees[method] = root
+	}
+
+	rt := &Route{
+		method:      method,
+		pattern:     path,
+		handler:     handler,
+		middlewares: mws,
+		group:       group,
+		router:      r,
+	}
+	if group != nil && group.host != nil {
+		rt.host = group.host.pattern
+	}
+	if err := root.insert(path, rt); err != nil {
+		panic(fmt.Sprintf("router: cannot register %s %s: %v", method, path, err))
+	}
+	r.routes = append(r.routes, rt)
+	return rt
+}
+
+// handle splits the handlers given to a registration method and adds the resulting route.
+// It panics if the handlers are not a list of middlewares followed by a handler.
+func (r *Router) handle(method, path string, handlers []any) *Route {
+	mws, handler, err := r.splitHandlers(handlers)
+	if err != nil {
+		panic(fmt.Sprintf("router: cannot register %s %s: %v", method, path, err))
+	}
+	return r.addRoute(method, path, nil, mws, handler)
+}
+
+// Handle registers a route for the given method, path and handler, checked at compile time
+// unlike the handlers of the GET method and its siblings.
+// The middlewares are applied to this route only, in the given order, as if given before the handler to GET:
+//
+//	r.Handle("GET", "/protected", handler, middleware.JWTAuthMiddleware("userClaims"))
+//
+// The method can be any HTTP method, including extension methods such as "PROPFIND".
+// It panics if the method is empty, or if the handler or a middleware is nil.
+// The handler will be wrapped with the middlewares defined for this router.
+func (r *Router) Handle(method, path string, handler context.HandlerFunc, mws ...middleware.Middleware) *Route {
+	if method == "" {
+		panic(fmt.Sprintf("router: cannot register %s: the method must not be empty", path))
+	}
+	if err := checkHandler(handler, mws); err != nil {
+		panic(fmt.Sprintf("router: cannot register %s %s: %v", method, path, err))
+	}
+	return r.addRoute(method, path, nil, slices.Clone(mws), handler)
+}
+
+// HandleE registers a route whose handler returns an error, which is rendered by the ErrorHandler.
+// It works as Handle otherwise.
+func (r *Router) HandleE(method, path string, handler context.HandlerFuncE, mws ...middleware.Middleware) *Route {
+	if handler == nil {
+		return r.Handle(method, path, nil, mws...)
+	}
+	return r.Handle(method, path, r.handlerE(handler), mws...)
 }
 
 // Use adds a middleware to the router.
+// Global middlewares apply to every route, whether it was registered before or after the call to Use.
+// For each route, global middlewares run first, then the middlewares of its groups from the outermost
+// to the innermost, then the middlewares given at registration, and finally the handler.
+// It panics if the router is already compiled, since the handler chains are built once by Compile,
+// or if a middleware is nil.
 func (r *Router) Use(m middleware.Middleware) {
+	if r.compiled.Load() {
+		panic("router: cannot add a middleware once the router is compiled")
+	}
+	if m == nil {
+		panic("router: cannot add a nil middleware")
+	}
 	r.Middlewares = append(r.Middlewares, m)
 }
 
-// GET registers a GET route with the specified path and handler.
+// GET registers a GET route with the specified path and handlers.
+// The last handler is the context.HandlerFunc of the route, and the ones before it are
+// middleware.Middleware applied to this route only, in the given order:
+//
+//	r.GET("/protected", middleware.JWTAuthMiddleware("userClaims"), handler)
+//
+// The handler can also be a context.HandlerFuncE returning an error, which is rendered by the ErrorHandler:
+//
+//	r.GET("/users/:id", func(c *context.Context) error {
+//		user, err := repo.Find(c.Param("id"))
+//		if err != nil {
+//			return err
+//		}
+//		c.RespondOK(user)
+//		return nil
+//	})
+//
 // The handler will be wrapped with the middlewares defined for this router.
-func (r *Router) GET(path string, handler context.HandlerFunc) {
-	r.addRoute("GET", path, handler)
+func (r *Router) GET(path string, handlers ...any) *Route {
+	return r.handle("GET", path, handlers)
 }
 
-// POST registers a POST route with the specified path and handler.
+// POST registers a POST route with the specified path and handlers.
+// Middlewares can be given before the handler, as for GET.
 // The handler will be wrapped with the middlewares defined for this router.
-func (r *Router) POST(path string, handler context.HandlerFunc) {
-	r.addRoute("POST", path, handler)
+func (r *Router) POST(path string, handlers ...any) *Route {
+	return r.handle("POST", path, handlers)
 }
 
-// PUT registers a PUT route with the specified path and handler.
+// PUT registers a PUT route with the specified path and handlers.
+// Middlewares can be given before the handler, as for GET.
 // The handler will be wrapped with the middlewares defined for this router.
-func (r *Router) PUT(path string, handler context.HandlerFunc) {
-	r.addRoute("PUT", path, handler)
+func (r *Router) PUT(path string, handlers ...any) *Route {
+	return r.handle("PUT", path, handlers)
 }
 
-// PATCH registers a PATCH route with the specified path and handler.
+// PATCH registers a PATCH route with the specified path and handlers.
+// Middlewares can be given before the handler, as for GET.
 // The handler will be wrapped with the middlewares defined for this router.
-func (r *Router) PATCH(path string, handler context.HandlerFunc) {
-	r.addRoute("PATCH", path, handler)
+func (r *Router) PATCH(path string, handlers ...any) *Route {
+	return r.handle("PATCH", path, handlers)
 }
 
-// DELETE registers a DELETE route with the specified path and handler.
+// DELETE registers a DELETE route with the specified path and handlers.
+// Middlewares can be given before the handler, as for GET.
 // The handler will be wrapped with the middlewares defined for this router.
-func (r *Router) DELETE(path string, handler context.HandlerFunc) {
-	r.addRoute("DELETE", path, handler)
+func (r *Router) DELETE(path string, handlers ...any) *Route {
+	return r.handle("DELETE", path, handlers)
+}
+
+// HEAD registers a HEAD route with the specified path and handlers.
+// HEAD requests are served by the matching GET route when no HEAD route is registered,
+// so this is only needed to handle HEAD requests differently.
+func (r *Router) HEAD(path string, handlers ...any) *Route {
+	return r.handle("HEAD", path, handlers)
+}
+
+// OPTIONS registers an OPTIONS route with the specified path and handlers.
+// OPTIONS requests are answered automatically when no OPTIONS route is registered,
+// so this is only needed to handle OPTIONS requests differently.
+func (r *Router) OPTIONS(path string, handlers ...any) *Route {
+	return r.handle("OPTIONS", path, handlers)
 }
 
 // Group creates a new route group with a common prefix.
@@ -87,15 +196,81 @@ func (r *Router) Group(prefix string) *routeGroup {
 	}
 }
 
+// mountMethods lists the HTTP methods routed to the handlers mounted with Mount.
+var mountMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// mountParam is the name of the catch-all parameter holding the path below a mount prefix.
+const mountParam = "mountpath"
+
+// Mount plugs an http.Handler under the given prefix, for every HTTP method.
+// The handler can be any net/http handler, such as pprof or a third-party admin UI, or another *Router.
+// The prefix is stripped from the request path before it reaches the handler, so that "/admin/users"
+// is seen as "/users" by a handler mounted on "/admin".
+// The prefix can contain parameters, which are stored in the request with context.WithRequestParams:
+// a mounted *Router copies them into the Params of its own contexts.
+// The handler will be wrapped with the middlewares defined for this router.
+func (r *Router) Mount(prefix string, handler http.Handler) {
+	r.mount(prefix, handler, nil)
+}
+
+// mount registers the routes forwarding the requests under prefix to the handler.
+// The group is the route group the handler is mounted through, if any,
+// so that the group middlewares also apply to the mounted handler.
+func (r *Router) mount(prefix string, handler http.Handler, group *routeGroup) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	forward := func(c *context.Context) {
+		var rest string
+		params := make(map[string]string, len(c.Params))
+		for _, p := range c.Params {
+			if p.Key == mountParam {
+				rest = p.Value
+				continue
+			}
+			params[p.Key] = p.Value
+		}
+
+		req := context.WithRequestParams(c.Request, params)
+		req.URL = new(url.URL)
+		*req.URL = *c.Request.URL
+		req.URL.Path = "/" + rest
+		req.URL.RawPath = ""
+		handler.ServeHTTP(c.Writer, req)
+	}
+
+	for _, method := range mountMethods {
+		if prefix != "" {
+			r.addRoute(method, prefix, group, nil, forward)
+		}
+		r.addRoute(method, prefix+"/*"+mountParam, group, nil, forward)
+	}
+}
+
 // ServeStatic serves static files from the specified directory.
 // The prefix is the URL path that will be used to access the static files.
 // The directory is the file system path where the static files are located.
 // It uses http.FileServer to serve the files and http.StripPrefix to remove the prefix from the file paths.
 // The handler will be wrapped with the middlewares defined for this router.
 func (r *Router) ServeStatic(prefix string, dir string) {
+	r.serveStatic(prefix, dir, nil)
+}
+
+// serveStatic registers the catch-all GET route serving the files of dir under prefix.
+// The group is the route group the static files are served through, if any,
+// so that the group middlewares also apply to the static file responses.
+func (r *Router) serveStatic(prefix string, dir string, group *routeGroup) {
 	fs := http.StripPrefix(prefix, http.FileServer(http.Dir(dir)))
 
-	r.handle("GET", prefix+"/*filepath", func(c *context.Context) {
+	r.addRoute("GET", prefix+"/*filepath", group, nil, func(c *context.Context) {
 		fs.ServeHTTP(c.Writer, c.Request)
 	})
 }
@@ -1,6 +1,10 @@
 package Router
 
 import (
+	"fmt"
+	"net/http"
+	"slices"
+
 	context "github.com/ines-mgg/LetsGoBack/Context"
 	middleware "github.com/ines-mgg/LetsGoBack/Middleware"
 )
@@ -12,60 +16,124 @@ import (
 // The router field is a pointer to the parent Router, allowing the group to add routes to it.
 // The middlewares field is a slice of middleware.Middleware that can be used to apply common functionality
 // to all routes in the group, such as logging, authentication, or error handling.
-func (g *routeGroup) handle(method string, path string, handler context.HandlerFunc) {
-	fullHandler := handler
-	for i := len(g.middlewares) - 1; i >= 0; i-- {
-		fullHandler = g.middlewares[i](fullHandler)
+// The handlers are a list of route middlewares followed by the handler, as for the Router registration methods.
+// The group middlewares wrap the route middlewares, which wrap the handler.
+func (g *routeGroup) handle(method string, path string, handlers []any) *Route {
+	mws, handler, err := g.router.splitHandlers(handlers)
+	if err != nil {
+		panic(fmt.Sprintf("router: cannot register %s %s: %v", method, g.prefix+path, err))
+	}
+	return g.router.addRoute(method, g.prefix+path, g, mws, handler)
+}
+
+// Handle registers a route for the given method, path and handler, as Router.Handle does.
+// The path is relative to the group's prefix, and the group middlewares wrap the given ones.
+func (g *routeGroup) Handle(method, path string, handler context.HandlerFunc, mws ...middleware.Middleware) *Route {
+	if method == "" {
+		panic(fmt.Sprintf("router: cannot register %s: the method must not be empty", g.prefix+path))
+	}
+	if err := checkHandler(handler, mws); err != nil {
+		panic(fmt.Sprintf("router: cannot register %s %s: %v", method, g.prefix+path, err))
 	}
-	g.router.addRoute(method, g.prefix+path, fullHandler)
+	return g.router.addRoute(method, g.prefix+path, g, slices.Clone(mws), handler)
+}
+
+// HandleE registers a route whose handler returns an error, as Router.HandleE does.
+// The path is relative to the group's prefix, and the group middlewares wrap the given ones.
+func (g *routeGroup) HandleE(method, path string, handler context.HandlerFuncE, mws ...middleware.Middleware) *Route {
+	if handler == nil {
+		return g.Handle(method, path, nil, mws...)
+	}
+	return g.Handle(method, path, g.router.handlerE(handler), mws...)
 }
 
 // Use adds middleware to the route group.
-// The middlewares will be applied to all routes defined in this group.
+// The middlewares will be applied to all routes defined in this group and in its sub-groups,
+// whether they were registered before or after the call to Use.
+// It panics if the router is already compiled, since the handler chains are built once by Compile,
+// or if a middleware is nil.
 func (g *routeGroup) Use(mw ...middleware.Middleware) {
+	if g.router.compiled.Load() {
+		panic("router: cannot add a middleware once the router is compiled")
+	}
+	for _, m := range mw {
+		if m == nil {
+			panic("router: cannot add a nil middleware")
+		}
+	}
 	g.middlewares = append(g.middlewares, mw...)
 }
 
-// GET registers a GET route with the specified path and handler.
+// allMiddlewares returns the middlewares of the group and of its parent groups,
+// from the outermost group to this one.
+func (g *routeGroup) allMiddlewares() []middleware.Middleware {
+	if g.parent == nil {
+		return g.middlewares
+	}
+	return append(g.parent.allMiddlewares(), g.middlewares...)
+}
+
+// GET registers a GET route with the specified path and handlers.
+// Middlewares can be given before the handler, to be applied to this route only.
 // The handler will be wrapped with the middlewares defined for this route group.
 // The path is relative to the group's prefix, allowing for organized route management.
-func (g *routeGroup) GET(path string, handler context.HandlerFunc) {
-	g.handle("GET", path, handler)
+func (g *routeGroup) GET(path string, handlers ...any) *Route {
+	return g.handle("GET", path, handlers)
+}
+
+// POST registers a POST route with the specified path and handlers.
+func (g *routeGroup) POST(path string, handlers ...any) *Route {
+	return g.handle("POST", path, handlers)
 }
 
-// POST registers a POST route with the specified path and handler.
-func (g *routeGroup) POST(path string, handler context.HandlerFunc) {
-	g.handle("POST", path, handler)
+// PUT registers a PUT route with the specified path and handlers.
+func (g *routeGroup) PUT(path string, handlers ...any) *Route {
+	return g.handle("PUT", path, handlers)
 }
 
-// PUT registers a PUT route with the specified path and handler.
-func (g *routeGroup) PUT(path string, handler context.HandlerFunc) {
-	g.handle("PUT", path, handler)
+// PATCH registers a PATCH route with the specified path and handlers.
+func (g *routeGroup) PATCH(path string, handlers ...any) *Route {
+	return g.handle("PATCH", path, handlers)
 }
 
-// PATCH registers a PATCH route with the specified path and handler.
-func (g *routeGroup) PATCH(path string, handler context.HandlerFunc) {
-	g.handle("PATCH", path, handler)
+// DELETE registers a DELETE route with the specified path and handlers.
+func (g *routeGroup) DELETE(path string, handlers ...any) *Route {
+	return g.handle("DELETE", path, handlers)
 }
 
-// DELETE registers a DELETE route with the specified path and handler.
-func (g *routeGroup) DELETE(path string, handler context.HandlerFunc) {
-	g.handle("DELETE", path, handler)
+// HEAD registers a HEAD route with the specified path and handlers.
+func (g *routeGroup) HEAD(path string, handlers ...any) *Route {
+	return g.handle("HEAD", path, handlers)
+}
+
+// OPTIONS registers an OPTIONS route with the specified path and handlers.
+func (g *routeGroup) OPTIONS(path string, handlers ...any) *Route {
+	return g.handle("OPTIONS", path, handlers)
 }
 
 // Group creates a new route group with a sub-path.
 // The sub-path is appended to the current group's prefix, allowing for nested route groups.
+// The middlewares of the current group also apply to the routes of the sub-group, and run before its own middlewares.
 func (g *routeGroup) Group(subPath string) *routeGroup {
 	return &routeGroup{
-		prefix:      g.prefix + subPath,
-		router:      g.router,
-		middlewares: append([]middleware.Middleware{}, g.middlewares...),
+		prefix: g.prefix + subPath,
+		router: g.router,
+		parent: g,
+		host:   g.host,
 	}
 }
 
 // ServeStatic serves static files from the specified directory.
 // The path is relative to the group's prefix, allowing for organized static file serving.
 // The directory is the file system path where the static files are located.
+// The static file responses are wrapped with the middlewares defined for this route group.
 func (g *routeGroup) ServeStatic(path string, dir string) {
-	g.router.ServeStatic(g.prefix+path, dir)
+	g.router.serveStatic(g.prefix+path, dir, g)
+}
+
+// Mount plugs an http.Handler under the given path, for every HTTP method.
+// The path is relative to the group's prefix, which is stripped along with the path before the request reaches the handler.
+// The handler will be wrapped with the middlewares defined for this route group.
+func (g *routeGroup) Mount(path string, handler http.Handler) {
+	g.router.mount(g.prefix+path, handler, g)
 }
@@ -0,0 +1,211 @@
+package Router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+	middleware "github.com/ines-mgg/LetsGoBack/Middleware"
+)
+
+// expectPanic runs fn and fails the test if it does not panic with a message containing want.
+func expectPanic(t *testing.T, want string, fn func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected a panic containing %q, got %q", want, msg)
+		}
+	}()
+	fn()
+}
+
+func TestRegisterNilMiddleware(t *testing.T) {
+	var nilMiddleware middleware.Middleware
+	var nilFunc func(context.HandlerFunc) context.HandlerFunc
+
+	r := NewRouter()
+	expectPanic(t, "cannot register GET /a: middleware 0 must not be nil", func() {
+		r.GET("/a", nilMiddleware, noop)
+	})
+	expectPanic(t, "cannot register GET /b: middleware 1 must not be nil", func() {
+		r.GET("/b", middleware.Middleware(func(next context.HandlerFunc) context.HandlerFunc { return next }), nilFunc, noop)
+	})
+	expectPanic(t, "cannot register POST /api/c: middleware 0 must not be nil", func() {
+		r.Group("/api").POST("/c", nilMiddleware, noop)
+	})
+	expectPanic(t, "cannot add a nil middleware", func() {
+		r.Use(nil)
+	})
+	expectPanic(t, "cannot add a nil middleware", func() {
+		r.Group("/api").Use(nilMiddleware)
+	})
+}
+
+func TestRegisterAfterCompile(t *testing.T) {
+	r := NewRouter()
+	rt := r.GET("/users/:id", noop)
+	r.Compile()
+
+	expectPanic(t, "routes cannot be added once the router is compiled", func() {
+		r.GET("/other", noop)
+	})
+	expectPanic(t, "routes cannot be named once the router is compiled", func() {
+		rt.Name("user.show")
+	})
+	expectPanic(t, "hosts cannot be added once the router is compiled", func() {
+		r.Host("api.example.com")
+	})
+	expectPanic(t, "cannot add a middleware once the router is compiled", func() {
+		r.Use(func(next context.HandlerFunc) context.HandlerFunc { return next })
+	})
+}
+
+func TestErrorHandler(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	r := NewRouter()
+	r.ErrorHandler = func(c *context.Context, err error) {
+		if errors.Is(err, errNoRows) {
+			err = context.ErrNotFound.Wrap(err)
+		}
+		context.DefaultErrorHandler(c, err)
+	}
+	r.GET("/users/:id", func(c *context.Context) error {
+		return fmt.Errorf("finding user %s: %w", c.Param("id"), errNoRows)
+	})
+	r.GET("/fail", func(c *context.Context) error { return errors.New("boom") })
+	r.GET("/ok", func(c *context.Context) error {
+		c.RespondOK("ok")
+		return nil
+	})
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/users/1", http.StatusNotFound},
+		{"/fail", http.StatusInternalServerError},
+		{"/ok", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d: %s", tt.path, tt.status, w.Code, w.Body)
+		}
+	}
+}
+
+func TestHandle(t *testing.T) {
+	var calls []string
+	trace := func(name string) middleware.Middleware {
+		return func(next context.HandlerFunc) context.HandlerFunc {
+			return func(c *context.Context) {
+				calls = append(calls, name)
+				next(c)
+			}
+		}
+	}
+
+	r := NewRouter()
+	var rt *Route = r.Handle("GET", "/users/:id", func(c *context.Context) {
+		calls = append(calls, "handler")
+		c.RespondOK(c.Param("id"))
+	}, trace("first"), trace("second"))
+	rt.Name("user.show")
+	r.Handle("PROPFIND", "/dav", func(c *context.Context) { c.RespondOK("dav") })
+	api := r.Group("/api")
+	api.Use(trace("group"))
+	api.Handle("POST", "/items", func(c *context.Context) {
+		calls = append(calls, "handler")
+		c.Writer.WriteHeader(http.StatusCreated)
+	}, trace("route"))
+	api.HandleE("DELETE", "/items/:id", func(c *context.Context) error {
+		return context.ErrNotFound
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		calls  []string
+	}{
+		{"GET", "/users/42", http.StatusOK, []string{"first", "second", "handler"}},
+		{"PROPFIND", "/dav", http.StatusOK, nil},
+		{"POST", "/api/items", http.StatusCreated, []string{"group", "route", "handler"}},
+		{"DELETE", "/api/items/1", http.StatusNotFound, []string{"group"}},
+	}
+	for _, tt := range tests {
+		calls = nil
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s %s: expected %d, got %d: %s", tt.method, tt.path, tt.status, w.Code, w.Body)
+		}
+		if !slices.Equal(calls, tt.calls) {
+			t.Errorf("%s %s: expected calls %v, got %v", tt.method, tt.path, tt.calls, calls)
+		}
+	}
+	if url, err := r.URL("user.show", map[string]string{"id": "7"}); err != nil || url != "/users/7" {
+		t.Errorf("expected /users/7, got %q, %v", url, err)
+	}
+}
+
+func TestHandleInvalid(t *testing.T) {
+	r := NewRouter()
+	expectPanic(t, "cannot register /a: the method must not be empty", func() {
+		r.Handle("", "/a", noop)
+	})
+	expectPanic(t, "cannot register GET /b: the handler must not be nil", func() {
+		r.Handle("GET", "/b", nil)
+	})
+	expectPanic(t, "cannot register GET /c: the handler must not be nil", func() {
+		r.HandleE("GET", "/c", nil)
+	})
+	expectPanic(t, "cannot register GET /api/d: middleware 1 must not be nil", func() {
+		r.Group("/api").Handle("GET", "/d", noop, func(next context.HandlerFunc) context.HandlerFunc { return next }, nil)
+	})
+}
+
+func TestHandlersSetAfterCompile(t *testing.T) {
+	r := NewRouter()
+	r.GET("/users", func(c *context.Context) error { return errors.New("boom") })
+	r.Use(func(next context.HandlerFunc) context.HandlerFunc {
+		return func(c *context.Context) {
+			c.Writer.Header().Set("X-Global", "1")
+			next(c)
+		}
+	})
+	r.Compile()
+
+	r.NotFoundHandler = func(c *context.Context) { c.Writer.WriteHeader(http.StatusTeapot) }
+	r.MethodNotAllowedHandler = func(c *context.Context) { c.Writer.WriteHeader(http.StatusConflict) }
+	r.ErrorHandler = func(c *context.Context, err error) { c.Writer.WriteHeader(http.StatusBadGateway) }
+	r.UseMiddlewaresOnNoRoute = true
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{"GET", "/missing", http.StatusTeapot},
+		{"POST", "/users", http.StatusConflict},
+		{"GET", "/users", http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.status, w.Code)
+		}
+		if w.Header().Get("X-Global") != "1" {
+			t.Errorf("%s %s: expected the global middlewares to run", tt.method, tt.path)
+		}
+	}
+}
@@ -1,116 +1,383 @@
 package Router
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 
 	context "github.com/ines-mgg/LetsGoBack/Context"
 )
 
-// matchPattern checks if the given path matches the specified pattern.
-// It supports dynamic segments prefixed with ":" and wildcard segments prefixed with "*".
-// It returns a map of parameters extracted from the path if it matches the pattern,
-// or nil and false if it does not match.
-// The function splits both the pattern and path into parts, iterating through them to check for matches.
-// If a part of the pattern starts with ":", it is treated as a dynamic segment and its value is stored in the params map.
-// If a part of the pattern starts with "*", it captures the rest of the path as a wildcard segment.
-// If the pattern and path match exactly, it returns the params map.
-func matchPattern(pattern, path string) (map[string]string, bool) {
-	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
-	pathParts := strings.Split(strings.Trim(path, "/"), "/")
-
-	params := make(map[string]string)
-
-	for i := 0; i < len(patternParts); i++ {
-		if i >= len(pathParts) {
-			return nil, false
+// findRoute resolves the route registered for the given method and path in the given routing trees.
+// It walks the routing tree of the method once and returns the matched route,
+// along with the parameters captured from dynamic segments.
+// The params slice is only allocated when the path actually contains parameters,
+// so that static routes are resolved without any allocation.
+func findRoute(trees map[string]*node, method, path string) (*Route, []param) {
+	root := trees[method]
+	if root == nil {
+		return nil, nil
+	}
+
+	var ps []param
+	rt := root.find(path, &ps)
+	if rt == nil {
+		return nil, nil
+	}
+	return rt, ps
+}
+
+// lookup resolves the route registered for the given host, method and path.
+// The routes of the host patterns matching the host are tried first, in priority order,
+// and the parameters captured from the host are returned before the path parameters.
+// The routes registered directly on the router are only used when no host pattern matches the host,
+// so that they are not reachable on the hosts served by Host groups.
+func (r *Router) lookup(host, method, path string) (*Route, []param) {
+	matched := false
+	for _, h := range r.hosts {
+		hps, ok := h.match(host)
+		if !ok {
+			continue
 		}
+		matched = true
+		if rt, ps := findRoute(h.trees, method, path); rt != nil {
+			return rt, append(hps, ps...)
+		}
+	}
+	if matched {
+		return nil, nil
+	}
+	return findRoute(r.trees, method, path)
+}
 
-		pp := patternParts[i]
-		pv := pathParts[i]
+// allowedMethods returns the sorted list of methods that have a route matching the given host and path.
+// HEAD is included when a GET route matches, since HEAD requests are served by GET handlers,
+// and OPTIONS is included when the router answers OPTIONS requests automatically.
+// The special "*" path used by server-wide OPTIONS requests matches every registered method.
+// The skip parameter excludes a method from the lookup, typically the method of the current request.
+func (r *Router) allowedMethods(host, path, skip string) []string {
+	methods := make(map[string]bool)
+	matched := false
+	for _, h := range r.hosts {
+		if _, ok := h.match(host); ok {
+			matched = true
+			for method := range h.trees {
+				methods[method] = true
+			}
+		}
+	}
+	if !matched {
+		for method := range r.trees {
+			methods[method] = true
+		}
+	}
 
-		if strings.HasPrefix(pp, ":") {
-			params[pp[1:]] = pv
-		} else if strings.HasPrefix(pp, "*") {
-			params[pp[1:]] = strings.Join(pathParts[i:], "/")
-			return params, true
-		} else if pp != pv {
-			return nil, false
+	var allowed []string
+	for method := range methods {
+		if method == skip {
+			continue
+		}
+		if path != "*" {
+			if rt, _ := r.lookup(host, method, path); rt == nil {
+				continue
+			}
 		}
+		allowed = append(allowed, method)
+	}
+	if len(allowed) == 0 {
+		return nil
 	}
 
-	if len(pathParts) != len(patternParts) {
-		return nil, false
+	if slices.Contains(allowed, http.MethodGet) && !slices.Contains(allowed, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	if r.HandleOPTIONS && !slices.Contains(allowed, http.MethodOptions) {
+		allowed = append(allowed, http.MethodOptions)
 	}
+	slices.Sort(allowed)
+	return allowed
+}
 
-	return params, true
+// Compile builds the final handler chain of every route, so that no middleware is wrapped per request.
+// Each chain is made of the global middlewares, then the middlewares of the route groups from the outermost
+// to the innermost, then the middlewares given at registration, and finally the handler.
+// Once compiled, the router is frozen: adding routes, route names, hosts or middlewares panics.
+// The not found and method not allowed handlers are also wrapped with the global middlewares,
+// used when UseMiddlewaresOnNoRoute is set. The NotFoundHandler, MethodNotAllowedHandler and ErrorHandler
+// are read when a request needs them, so that they can still be set once the router is compiled,
+// as long as no request is being served.
+// The Views, if any, are attached to the router for the url template function and loaded,
+// and Compile panics if their templates cannot be parsed.
+// Compile is called implicitly by Listen and by the first request served, and only runs once.
+func (r *Router) Compile() {
+	r.compileOnce.Do(func() {
+		for _, rt := range r.routes {
+			handler := chain(rt.handler, rt.middlewares)
+			if rt.group != nil {
+				handler = chain(handler, rt.group.allMiddlewares())
+			}
+			rt.chain = chain(handler, r.Middlewares)
+		}
+
+		r.optionsHandler = chain(func(c *context.Context) {
+			c.Writer.WriteHeader(http.StatusNoContent)
+		}, r.Middlewares)
+
+		r.notFoundHandler = r.notFound
+		r.methodNotAllowedHandler = r.methodNotAllowed
+		r.notFoundChain = chain(r.notFoundHandler, r.Middlewares)
+		r.methodNotAllowedChain = chain(r.methodNotAllowedHandler, r.Middlewares)
+
+		if r.Views != nil {
+			r.Views.urls = r
+			r.Views.reload = r.DevMode
+			if err := r.Views.Load(); err != nil {
+				panic(fmt.Sprintf("router: cannot load views: %v", err))
+			}
+		}
+
+		r.compiled.Store(true)
+	})
+}
+
+// newContext acquires the context of a request served by the router from the context pool.
+// It links the context to the router so that handlers can build URLs from named routes,
+// and passes the development mode, the error format, the body limits and the views of the router on to the context.
+// The context must be released with context.ReleaseContext once the handler chain returns.
+func (r *Router) newContext(w http.ResponseWriter, req *http.Request) *context.Context {
+	ctx := context.AcquireContext(w, req)
+	ctx.Router = r
+	ctx.DevMode = r.DevMode
+	ctx.ProblemDetails = r.ProblemDetails
+	ctx.BodyConfig = r.BodyConfig
+	if r.Views != nil {
+		ctx.Views = r.Views
+	}
+	return ctx
+}
+
+// serveContext runs the handler with a pooled context for the request,
+// and releases the context once the handler returns.
+// The status code set by the handler is sent if the handler did not write the body.
+func (r *Router) serveContext(w http.ResponseWriter, req *http.Request, handler context.HandlerFunc) {
+	ctx := r.newContext(w, req)
+	defer context.ReleaseContext(ctx)
+	handler(ctx)
+	ctx.Writer.WriteHeaderNow()
+}
+
+// serveRoute runs the handler chain of a matched route.
+// It acquires a pooled context and stores the extracted parameters and the matched route
+// before calling the chain built by Compile, then releases the context once the chain returns.
+// The status code set by the chain is sent if it did not write the body, as the writer of the context defers it.
+// The raw parameter reports whether the parameters were captured from the escaped path,
+// in which case they are unescaped when UnescapePathValues is set.
+func (r *Router) serveRoute(w http.ResponseWriter, req *http.Request, rt *Route, ps []param, raw bool) {
+	ctx := r.newContext(w, req)
+	defer context.ReleaseContext(ctx)
+	ctx.Route = rt.pattern
+	ctx.RouteName = rt.name
+	for _, p := range ps {
+		value := p.value
+		if raw && r.UnescapePathValues {
+			if v, err := url.PathUnescape(value); err == nil {
+				value = v
+			}
+		}
+		ctx.AddParam(p.key, value)
+	}
+
+	rt.chain(ctx)
+	ctx.Writer.WriteHeaderNow()
+}
+
+// headResponseWriter is an http.ResponseWriter used to serve HEAD requests with GET handlers.
+// Headers and status code are forwarded to the underlying writer while the body is discarded.
+// The status code is held back until finish, so that the Content-Length header can be set to the size
+// of the body the GET handler would have sent, as for the GET request.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+	sent   bool
+}
+
+// WriteHeader records the status code, sent by finish. Informational 1xx status codes are sent immediately.
+func (w *headResponseWriter) WriteHeader(code int) {
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+// Write discards the body and reports it as fully written, so that handlers behave as for a GET request.
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.size += len(b)
+	return len(b), nil
+}
+
+// Flush sends the status code and headers without Content-Length, since the size of the body is not known yet,
+// and flushes the underlying writer.
+func (w *headResponseWriter) Flush() {
+	w.sendHeader()
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// finish sends the status code and headers once the handler returned,
+// with the Content-Length header set to the size of the discarded body unless the handler set it.
+func (w *headResponseWriter) finish() {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	h := w.Header()
+	if !w.sent && w.size > 0 && status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified &&
+		h.Get("Content-Length") == "" && h.Get("Transfer-Encoding") == "" {
+		h.Set("Content-Length", strconv.Itoa(w.size))
+	}
+	w.sendHeader()
+}
+
+// sendHeader sends the recorded status code to the underlying writer, if it was not sent yet.
+func (w *headResponseWriter) sendHeader() {
+	if w.sent {
+		return
+	}
+	w.sent = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// Unwrap returns the underlying http.ResponseWriter, so that http.ResponseController can reach it.
+func (w *headResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
 }
 
 // ServeHTTP is the main entry point for handling HTTP requests.
-// It checks if a handler exists for the request method and path.
-// If a static handler is found, it creates a new context and applies middlewares in reverse order.
-// If a dynamic route matches, it extracts parameters and applies middlewares.
-// If no handler is found, it checks for method not allowed and not found handlers.
-// If no handlers are defined, it falls back to the default http.NotFound handler.
-// It uses the context package to create a new context for each request,
+// It looks up the route matching the request host, method and path in the routing trees.
+// If a route matches, it creates a new context, stores the extracted parameters and runs the handler chain of the route.
+// The router is compiled on the first request if Compile was not called before.
+// HEAD requests without a dedicated route are served by the matching GET route, with the body discarded
+// and the Content-Length header set to the size of the body of the GET response.
+// OPTIONS requests without a dedicated route are answered automatically with the Allow header when HandleOPTIONS is set.
+// If the path matches routes registered for other methods, it responds with 405 Method Not Allowed and the Allow header,
+// using the MethodNotAllowedHandler if defined.
+// Only then are requests whose path matches a route with or without a trailing slash, or once cleaned and compared
+// case-insensitively, redirected according to RedirectTrailingSlash and RedirectFixedPath,
+// except for CORS preflight requests, which browsers do not follow redirects for.
+// Otherwise, it uses the NotFoundHandler if defined, or falls back to the default http.NotFound handler.
+// When UseMiddlewaresOnNoRoute is set, the global middlewares also run for these 404 and 405 responses,
+// with an empty Route on the context since no route matched.
+// It uses the context package to acquire a pooled context for each request,
 // allowing access to request and response data, as well as any parameters extracted from dynamic routes.
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Compile()
+
+	host := requestHost(req.Host)
 	method := req.Method
 	path := req.URL.Path
+	raw := false
+	if r.UseRawPath && req.URL.RawPath != "" {
+		path = req.URL.RawPath
+		raw = true
+	}
 
-	if handler, ok := r.Handlers[method][path]; ok {
-		ctx := context.NewContext(w, req)
-		for i := len(r.Middlewares) - 1; i >= 0; i-- {
-			handler = r.Middlewares[i](handler)
-		}
-
-		handler(ctx)
+	if rt, ps := r.lookup(host, method, path); rt != nil {
+		r.serveRoute(w, req, rt, ps, raw)
 		return
 	}
 
-	for _, route := range r.DynamicRoutes {
-		if route.method != method {
-			continue
+	if method == http.MethodHead {
+		if rt, ps := r.lookup(host, http.MethodGet, path); rt != nil {
+			hw := &headResponseWriter{ResponseWriter: w}
+			r.serveRoute(hw, req, rt, ps, raw)
+			hw.finish()
+			return
 		}
-		params, ok := matchPattern(route.pattern, path)
-		if ok {
-			ctx := context.NewContext(w, req)
-			ctx.Params = params
-
-			// middlewares
-			handler := route.handler
-			for i := len(r.Middlewares) - 1; i >= 0; i-- {
-				handler = r.Middlewares[i](handler)
-			}
+	}
 
-			handler(ctx)
+	if method == http.MethodOptions && r.HandleOPTIONS {
+		if allowed := r.allowedMethods(host, path, method); allowed != nil {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			r.serveContext(w, req, r.optionsHandler)
 			return
 		}
 	}
 
-	for m, routes := range r.Handlers {
-		if m != method {
-			if _, ok := routes[path]; ok && r.MethodNotAllowedHandler != nil {
-				ctx := context.NewContext(w, req)
-				r.MethodNotAllowedHandler(ctx)
-				return
-			}
+	if r.HandleMethodNotAllowed && path != "*" {
+		if allowed := r.allowedMethods(host, path, method); allowed != nil {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			r.serveContext(w, req, r.noRoute(r.methodNotAllowedHandler, r.methodNotAllowedChain))
+			return
 		}
 	}
 
-	if r.NotFoundHandler != nil {
-		ctx := context.NewContext(w, req)
-		r.NotFoundHandler(ctx)
+	// CORS preflight requests are never redirected, since browsers reject redirected preflights.
+	preflight := method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+	if method != http.MethodConnect && path != "*" && !preflight {
+		if fixed, ok := r.redirectPath(host, method, path); ok {
+			redirect(w, req, fixed, raw)
+			return
+		}
+	}
+
+	r.serveContext(w, req, r.noRoute(r.notFoundHandler, r.notFoundChain))
+}
+
+// noRoute returns the handler of the requests matching no route, given the handler itself and the handler
+// wrapped with the global middlewares, according to UseMiddlewaresOnNoRoute.
+func (r *Router) noRoute(handler, chained context.HandlerFunc) context.HandlerFunc {
+	if r.UseMiddlewaresOnNoRoute {
+		return chained
+	}
+	return handler
+}
+
+// notFound runs the NotFoundHandler, or falls back to the default http.NotFound handler.
+func (r *Router) notFound(c *context.Context) {
+	if h := r.NotFoundHandler; h != nil {
+		h(c)
 		return
 	}
+	http.NotFound(c.Writer, c.Request)
+}
 
-	http.NotFound(w, req)
+// methodNotAllowed runs the MethodNotAllowedHandler, or falls back to a plain 405 Method Not Allowed response.
+func (r *Router) methodNotAllowed(c *context.Context) {
+	if h := r.MethodNotAllowedHandler; h != nil {
+		h(c)
+		return
+	}
+	http.Error(c.Writer, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+// handleError runs the ErrorHandler on the error returned by a handler, or falls back to context.DefaultErrorHandler.
+func (r *Router) handleError(c *context.Context, err error) {
+	if h := r.ErrorHandler; h != nil {
+		h(c, err)
+		return
+	}
+	context.DefaultErrorHandler(c, err)
 }
 
 // Listen starts the HTTP server on the given address using the router as handler.
-// It uses http.ListenAndServe to bind the router to the specified address.
+// It binds the router to the specified address with the default server configuration, without timeouts.
 // This function is typically called in the main function of the application to start serving requests.
-// It returns an error if the server fails to start, allowing the caller to handle it appropriately.
+// It returns an error if the server fails to start, allowing the caller to handle it appropriately,
+// or nil once the server has been stopped gracefully with Shutdown.
+// The router is compiled before the server starts, so that routes and middlewares can no longer be added.
+// Use ListenWithConfig to configure timeouts and graceful shutdown on signals.
 func (r *Router) Listen(addr string) error {
-	return http.ListenAndServe(addr, r)
+	return r.ListenWithConfig(addr, ServerConfig{})
 }
@@ -0,0 +1,150 @@
+package Router
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+	middleware "github.com/ines-mgg/LetsGoBack/Middleware"
+)
+
+func TestMethodNotAllowedAndOptions(t *testing.T) {
+	r := NewRouter()
+	r.GET("/users", noop)
+	r.POST("/users", noop)
+	r.DELETE("/users/:id", noop)
+	r.PUT("/users/:id", noop)
+	r.OPTIONS("/custom", func(c *context.Context) { c.SetStatus(http.StatusOK) })
+	r.GET("/custom", noop)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		allow  string
+	}{
+		{"PUT", "/users", http.StatusMethodNotAllowed, "GET, HEAD, OPTIONS, POST"},
+		{"GET", "/users/1", http.StatusMethodNotAllowed, "DELETE, OPTIONS, PUT"},
+		{"OPTIONS", "/users", http.StatusNoContent, "GET, HEAD, OPTIONS, POST"},
+		{"OPTIONS", "/users/1", http.StatusNoContent, "DELETE, OPTIONS, PUT"},
+		{"OPTIONS", "*", http.StatusNoContent, "DELETE, GET, HEAD, OPTIONS, POST, PUT"},
+		// A dedicated OPTIONS route is served instead of the automatic answer.
+		{"OPTIONS", "/custom", http.StatusOK, ""},
+		{"OPTIONS", "/unknown", http.StatusNotFound, ""},
+		{"GET", "/unknown", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.status, w.Code)
+		}
+		if got := w.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("%s %s: expected Allow %q, got %q", tt.method, tt.path, tt.allow, got)
+		}
+	}
+}
+
+func TestMethodNotAllowedDisabled(t *testing.T) {
+	r := NewRouter()
+	r.HandleMethodNotAllowed = false
+	r.HandleOPTIONS = false
+	r.GET("/users", noop)
+
+	for _, method := range []string{"POST", "OPTIONS"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, "/users", nil))
+		if w.Code != http.StatusNotFound || w.Header().Get("Allow") != "" {
+			t.Errorf("%s: expected a 404 without Allow header, got %d with %q", method, w.Code, w.Header().Get("Allow"))
+		}
+	}
+}
+
+func TestCustomMethodNotAllowedAndOptionsHandlers(t *testing.T) {
+	r := NewRouter()
+	r.MethodNotAllowedHandler = func(c *context.Context) {
+		c.Render(http.StatusMethodNotAllowed, "text/plain", "allow: "+c.Writer.Header().Get("Allow"))
+	}
+	// The automatic OPTIONS answer runs the global middlewares, so that they can customize it, as CORS does.
+	r.Use(func(next context.HandlerFunc) context.HandlerFunc {
+		return func(c *context.Context) {
+			if c.Method == http.MethodOptions {
+				c.Writer.Header().Set("X-Options", "custom")
+			}
+			next(c)
+		}
+	})
+	r.GET("/users", noop)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("DELETE", "/users", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Body.String() != "allow: GET, HEAD, OPTIONS" {
+		t.Errorf("expected the custom 405 handler, got %d: %s", w.Code, w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("OPTIONS", "/users", nil))
+	if w.Code != http.StatusNoContent || w.Header().Get("X-Options") != "custom" {
+		t.Errorf("expected the middlewares to run on the OPTIONS answer, got %d with %v", w.Code, w.Header())
+	}
+}
+
+func TestHeadServedByGet(t *testing.T) {
+	r := NewRouter()
+	r.GET("/users", func(c *context.Context) { c.RespondOK([]string{"ada", "linus"}) })
+	r.GET("/empty", func(c *context.Context) { c.SetStatus(http.StatusNoContent) })
+	r.GET("/sized", func(c *context.Context) {
+		c.Writer.Header().Set("Content-Length", "5")
+		c.Writer.Write([]byte("hello"))
+	})
+	r.HEAD("/head", func(c *context.Context) { c.Writer.Header().Set("X-Head", "dedicated") })
+	r.GET("/head", noop)
+	r.Use(middleware.RequestIDMiddleware())
+
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/users", http.StatusOK},
+		{"/empty", http.StatusNoContent},
+		{"/sized", http.StatusOK},
+	}
+	for _, tt := range tests {
+		get, err := http.Get(srv.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(get.Body)
+		get.Body.Close()
+
+		resp, err := http.Head(srv.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		headBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status || len(headBody) != 0 {
+			t.Errorf("HEAD %s: expected %d without body, got %d with %q", tt.path, tt.status, resp.StatusCode, headBody)
+		}
+		if tt.status == http.StatusOK && resp.ContentLength != int64(len(body)) {
+			t.Errorf("HEAD %s: expected Content-Length %d as for GET, got %d", tt.path, len(body), resp.ContentLength)
+		}
+		if tt.path == "/users" && resp.Header.Get("Content-Type") != get.Header.Get("Content-Type") {
+			t.Errorf("HEAD %s: expected the headers of the GET response, got %v", tt.path, resp.Header)
+		}
+	}
+
+	resp, err := http.Head(srv.URL + "/head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("X-Head") != "dedicated" {
+		t.Errorf("expected the dedicated HEAD route to be served, got %v", resp.Header)
+	}
+}
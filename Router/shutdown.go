@@ -0,0 +1,160 @@
+package Router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// defaultShutdownTimeout is the time given to active requests to complete
+// when the server is shut down on a signal and ServerConfig.ShutdownTimeout is not set.
+const defaultShutdownTimeout = 30 * time.Second
+
+// newServer creates the http.Server serving the router on the given address with the given configuration.
+// HTTP/2 over cleartext connections is enabled when cfg.H2C is set, in addition to HTTP/1
+// and to HTTP/2 over TLS, so that the same configuration can be used for TLS servers.
+func (r *Router) newServer(addr string, cfg ServerConfig) *http.Server {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           r,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+
+	if cfg.H2C {
+		var protocols http.Protocols
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		srv.Protocols = &protocols
+	}
+	return srv
+}
+
+// serve registers the server on the router and runs it with the given function, typically srv.ListenAndServe.
+// The router is compiled before the server starts, so that routes and middlewares can no longer be added.
+// When the server is stopped by Shutdown, it waits for the shutdown to complete, so that the caller
+// does not exit while active requests are still being drained, and returns the result of the shutdown.
+// It returns http.ErrServerClosed if the router was already shut down.
+func (r *Router) serve(srv *http.Server, cfg ServerConfig, run func() error) error {
+	r.Compile()
+
+	r.serverMu.Lock()
+	if r.shuttingDown {
+		r.serverMu.Unlock()
+		return http.ErrServerClosed
+	}
+	r.servers = append(r.servers, srv)
+	r.serverMu.Unlock()
+
+	if cfg.HandleSignals {
+		stop := r.shutdownOnSignal(cfg.ShutdownTimeout)
+		defer stop()
+	}
+
+	err := run()
+	if errors.Is(err, http.ErrServerClosed) {
+		<-r.shutdownDone
+		return r.shutdownErr
+	}
+	return err
+}
+
+// shutdownWithin shuts the router down, giving active requests the timeout to complete,
+// or defaultShutdownTimeout if it is zero.
+// It is used when the shutdown is not requested by the caller, on a signal or when a server fails.
+func (r *Router) shutdownWithin(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	r.Shutdown(ctx)
+}
+
+// shutdownOnSignal shuts the router down when the process receives SIGINT or SIGTERM.
+// Active requests are given the timeout to complete, or defaultShutdownTimeout if it is zero.
+// It returns a function that stops listening for the signals.
+func (r *Router) shutdownOnSignal(timeout time.Duration) func() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-sigs:
+			r.shutdownWithin(timeout)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
+
+// ListenWithConfig starts the HTTP server on the given address using the router as handler,
+// with the timeouts and limits of the given configuration.
+// When cfg.HandleSignals is set, the server is shut down gracefully on SIGINT or SIGTERM.
+// It returns nil once the server has been shut down gracefully, or the error that stopped it.
+// Usage example:
+//
+//	err := r.ListenWithConfig(":8080", router.ServerConfig{
+//	    ReadHeaderTimeout: 5 * time.Second,
+//	    WriteTimeout:      10 * time.Second,
+//	    IdleTimeout:       60 * time.Second,
+//	    HandleSignals:     true,
+//	})
+func (r *Router) ListenWithConfig(addr string, cfg ServerConfig) error {
+	srv := r.newServer(addr, cfg)
+	return r.serve(srv, cfg, srv.ListenAndServe)
+}
+
+// OnShutdown registers a function to run when the router is shut down,
+// after the active requests have been drained, such as closing database connections.
+// Hooks run in registration order.
+func (r *Router) OnShutdown(fn func()) {
+	r.serverMu.Lock()
+	defer r.serverMu.Unlock()
+	r.shutdownHooks = append(r.shutdownHooks, fn)
+}
+
+// Shutdown gracefully stops the servers started by the router.
+// It stops accepting new connections, waits for the active requests to complete until the context is done,
+// then runs the hooks registered with OnShutdown.
+// It returns the context error if the deadline is reached before all requests have completed.
+// Calling Shutdown more than once waits for the first shutdown to complete and returns its result.
+func (r *Router) Shutdown(ctx context.Context) error {
+	r.serverMu.Lock()
+	if r.shuttingDown {
+		r.serverMu.Unlock()
+		<-r.shutdownDone
+		return r.shutdownErr
+	}
+	r.shuttingDown = true
+	servers := r.servers
+	hooks := r.shutdownHooks
+	r.serverMu.Unlock()
+
+	var errs []error
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, hook := range hooks {
+		hook()
+	}
+
+	r.shutdownErr = errors.Join(errs...)
+	close(r.shutdownDone)
+	return r.shutdownErr
+}
@@ -0,0 +1,132 @@
+package Router
+
+import (
+	stdcontext "context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+)
+
+func TestNewServerProtocols(t *testing.T) {
+	r := NewRouter()
+	if srv := r.newServer(":0", ServerConfig{}); srv.Protocols != nil {
+		t.Errorf("expected the net/http default protocols, got %v", srv.Protocols)
+	}
+
+	srv := r.newServer(":0", ServerConfig{H2C: true})
+	if p := srv.Protocols; p == nil || !p.HTTP1() || !p.HTTP2() || !p.UnencryptedHTTP2() {
+		t.Errorf("expected HTTP/1, HTTP/2 and unencrypted HTTP/2, got %v", p)
+	}
+}
+
+// serveAsync serves the router on a new local TCP listener in the background,
+// and returns the address of the listener and a channel receiving the result of Serve.
+func serveAsync(t *testing.T, r *Router) (string, <-chan error) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- r.Serve(l) }()
+	return l.Addr().String(), done
+}
+
+func TestShutdownDrainsRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	r := NewRouter()
+	r.GET("/slow", func(c *context.Context) {
+		close(started)
+		<-release
+		c.RespondOK("done")
+	})
+
+	var mu sync.Mutex
+	var hooks []string
+	r.OnShutdown(func() { mu.Lock(); hooks = append(hooks, "db"); mu.Unlock() })
+	r.OnShutdown(func() { mu.Lock(); hooks = append(hooks, "cache"); mu.Unlock() })
+
+	addr, done := serveAsync(t, r)
+	resp := make(chan *http.Response, 1)
+	go func() {
+		res, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			t.Error(err)
+		}
+		resp <- res
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- r.Shutdown(stdcontext.Background()) }()
+
+	// New connections are refused while the active request is drained.
+	time.Sleep(50 * time.Millisecond)
+	if _, err := net.DialTimeout("tcp", addr, time.Second); err == nil {
+		t.Error("expected new connections to be refused during the shutdown")
+	}
+	select {
+	case <-shutdown:
+		t.Fatal("expected Shutdown to wait for the active request")
+	default:
+	}
+
+	close(release)
+	if res := <-resp; res == nil || res.StatusCode != http.StatusOK {
+		t.Errorf("expected the active request to complete, got %v", res)
+	} else {
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("expected a graceful shutdown, got %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("expected Serve to return nil after a graceful shutdown, got %v", err)
+	}
+	if !slices.Equal(hooks, []string{"db", "cache"}) {
+		t.Errorf("expected the hooks to run in registration order, got %v", hooks)
+	}
+
+	// Shutting down again returns the result of the first shutdown, and the router cannot serve anymore.
+	if err := r.Shutdown(stdcontext.Background()); err != nil {
+		t.Errorf("expected the second Shutdown to return nil, got %v", err)
+	}
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	defer l.Close()
+	if err := r.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+		t.Errorf("expected http.ErrServerClosed once shut down, got %v", err)
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	r := NewRouter()
+	r.GET("/stuck", func(c *context.Context) {
+		close(started)
+		<-release
+	})
+
+	addr, done := serveAsync(t, r)
+	go http.Get("http://" + addr + "/stuck")
+	<-started
+
+	ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := r.Shutdown(ctx); !errors.Is(err, stdcontext.DeadlineExceeded) {
+		t.Errorf("expected the deadline to be exceeded, got %v", err)
+	}
+	if err := <-done; !errors.Is(err, stdcontext.DeadlineExceeded) {
+		t.Errorf("expected Serve to return the shutdown error, got %v", err)
+	}
+}
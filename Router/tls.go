@@ -0,0 +1,166 @@
+package Router
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// certReloadInterval is the minimum time between two checks of the certificate files on disk.
+const certReloadInterval = 10 * time.Second
+
+// certReloader serves a TLS certificate loaded from disk and reloads it when the files change,
+// so that renewed certificates are picked up without restarting the server.
+// The files are checked during TLS handshakes, at most once per certReloadInterval,
+// by comparing their modification times with the ones of the loaded certificate.
+// If the new files cannot be loaded, for example while they are being written, the previous certificate is kept.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certMod time.Time
+	keyMod  time.Time
+	checked time.Time
+}
+
+// newCertReloader loads the certificate and key files and returns a reloader serving them.
+// It returns an error if the files cannot be read or do not hold a valid key pair.
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	c := &certReloader{certFile: certFile, keyFile: keyFile}
+	certMod, keyMod, err := c.modTimes()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.load(certMod, keyMod); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// modTimes returns the modification times of the certificate and key files.
+func (c *certReloader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(c.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	keyInfo, err := os.Stat(c.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
+
+// load reads the key pair from disk and stores it along with the modification times of its files.
+func (c *certReloader) load(certMod, keyMod time.Time) error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+	c.cert = &cert
+	c.certMod = certMod
+	c.keyMod = keyMod
+	c.checked = time.Now()
+	return nil
+}
+
+// GetCertificate returns the current certificate, reloading it first if its files changed on disk.
+// It has the signature of tls.Config.GetCertificate.
+func (c *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.checked) < certReloadInterval {
+		return c.cert, nil
+	}
+	c.checked = time.Now()
+
+	certMod, keyMod, err := c.modTimes()
+	if err != nil {
+		log.Printf("[WARN] Cannot check TLS certificate %s: %v", c.certFile, err)
+		return c.cert, nil
+	}
+	if certMod.Equal(c.certMod) && keyMod.Equal(c.keyMod) {
+		return c.cert, nil
+	}
+	if err := c.load(certMod, keyMod); err != nil {
+		log.Printf("[WARN] Cannot reload TLS certificate %s: %v", c.certFile, err)
+		return c.cert, nil
+	}
+	log.Printf("[INFO] TLS certificate %s reloaded", c.certFile)
+	return c.cert, nil
+}
+
+// newTLSConfig builds the tls.Config of a server from the given TLSConfig.
+// The certificate is served through a certReloader, and client certificates are verified
+// against the authorities of ClientCAFile when it is set.
+func newTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("TLS certificate and key files are required")
+	}
+	reloader, err := newCertReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+		ClientAuth:     cfg.ClientAuth,
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificate found in %s", cfg.ClientCAFile)
+		}
+		tlsCfg.ClientCAs = pool
+		if tlsCfg.ClientAuth == tls.NoClientCert {
+			tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	return tlsCfg, nil
+}
+
+// ListenTLS starts the HTTPS server on the given address using the router as handler.
+// The certificate and key files are reloaded automatically when they change on disk.
+// It returns an error if the certificate cannot be loaded or the server fails to start,
+// or nil once the server has been stopped gracefully with Shutdown.
+func (r *Router) ListenTLS(addr, certFile, keyFile string) error {
+	return r.ListenTLSWithConfig(addr, TLSConfig{CertFile: certFile, KeyFile: keyFile}, ServerConfig{})
+}
+
+// ListenTLSWithConfig starts the HTTPS server on the given address using the router as handler,
+// with the TLS settings of tlsCfg and the timeouts and limits of cfg.
+// Setting tlsCfg.ClientCAFile enables mutual TLS: client certificates are verified against these authorities,
+// and the verified certificate is available in handlers with Context.PeerCertificate.
+// Usage example:
+//
+//	err := r.ListenTLSWithConfig(":8443", router.TLSConfig{
+//	    CertFile:     "server.crt",
+//	    KeyFile:      "server.key",
+//	    ClientCAFile: "clients-ca.crt",
+//	}, router.ServerConfig{HandleSignals: true})
+func (r *Router) ListenTLSWithConfig(addr string, tlsCfg TLSConfig, cfg ServerConfig) error {
+	config, err := newTLSConfig(tlsCfg)
+	if err != nil {
+		return err
+	}
+
+	srv := r.newServer(addr, cfg)
+	srv.TLSConfig = config
+	return r.serve(srv, cfg, func() error {
+		return srv.ListenAndServeTLS("", "")
+	})
+}
@@ -0,0 +1,203 @@
+package Router
+
+import (
+	stdcontext "context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+)
+
+// writeCert generates a self-signed certificate for the common name, usable by servers on 127.0.0.1 and by clients,
+// and writes it with its key as PEM files in dir. It returns the paths of the files and the parsed key pair.
+func writeCert(t *testing.T, dir, name string) (certFile, keyFile string, pair tls.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	pair, err = tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, pair
+}
+
+// commonName returns the common name of the leaf certificate served by the reloader.
+func commonName(t *testing.T, c *certReloader) string {
+	t.Helper()
+	cert, err := c.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeCert(t, dir, "first")
+	reloader, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := commonName(t, reloader); name != "first" {
+		t.Fatalf("expected the first certificate, got %s", name)
+	}
+
+	// The renewed pair replaces the files, which are only checked once certReloadInterval has elapsed.
+	renewedCert, renewedKey, _ := writeCert(t, dir, "renewed")
+	later := time.Now().Add(time.Minute)
+	for _, rename := range [][2]string{{renewedCert, certFile}, {renewedKey, keyFile}} {
+		if err := os.Rename(rename[0], rename[1]); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(rename[1], later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if name := commonName(t, reloader); name != "first" {
+		t.Errorf("expected the files not to be checked before the interval, got %s", name)
+	}
+	reloader.checked = time.Now().Add(-certReloadInterval)
+	if name := commonName(t, reloader); name != "renewed" {
+		t.Errorf("expected the renewed certificate, got %s", name)
+	}
+
+	// A pair that cannot be loaded, such as while it is being written, keeps the current certificate.
+	if err := os.WriteFile(certFile, []byte("partial"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	later = later.Add(time.Minute)
+	os.Chtimes(certFile, later, later)
+	reloader.checked = time.Now().Add(-certReloadInterval)
+	if name := commonName(t, reloader); name != "renewed" {
+		t.Errorf("expected the renewed certificate to be kept, got %s", name)
+	}
+
+	if _, err := newCertReloader(filepath.Join(dir, "missing.crt"), keyFile); err == nil {
+		t.Error("expected an error for a missing certificate")
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, serverKey, serverPair := writeCert(t, dir, "server")
+	clientCA, _, clientPair := writeCert(t, dir, "client")
+	_, _, unknownPair := writeCert(t, dir, "unknown")
+
+	tlsCfg, err := newTLSConfig(TLSConfig{CertFile: serverCert, KeyFile: serverKey, ClientCAFile: clientCA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsCfg.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("expected client certificates to be required, got %v", tlsCfg.ClientAuth)
+	}
+
+	r := NewRouter()
+	r.GET("/whoami", func(c *context.Context) {
+		if cert := c.PeerCertificate(); cert != nil {
+			c.Writer.Write([]byte(cert.Subject.CommonName))
+		}
+	})
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go r.Serve(tls.NewListener(l, tlsCfg))
+	defer r.Shutdown(stdcontext.Background())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(serverPair.Leaf)
+	client := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs}}}
+	}
+	url := "https://" + l.Addr().String() + "/whoami"
+
+	resp, err := client(clientPair).Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "client" {
+		t.Errorf("expected the client certificate to be available to the handler, got %q", body)
+	}
+
+	for name, certs := range map[string][]tls.Certificate{"no certificate": nil, "unknown authority": {unknownPair}} {
+		if resp, err := client(certs...).Get(url); err == nil {
+			resp.Body.Close()
+			t.Errorf("%s: expected the handshake to fail", name)
+		}
+	}
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeCert(t, dir, "server")
+	notPEM := filepath.Join(dir, "ca.txt")
+	os.WriteFile(notPEM, []byte("not a certificate"), 0600)
+
+	for name, cfg := range map[string]TLSConfig{
+		"missing key":     {CertFile: certFile},
+		"mismatched pair": {CertFile: certFile, KeyFile: certFile},
+		"invalid CA file": {CertFile: certFile, KeyFile: keyFile, ClientCAFile: notPEM},
+		"missing CA file": {CertFile: certFile, KeyFile: keyFile, ClientCAFile: filepath.Join(dir, "missing.crt")},
+	} {
+		if _, err := newTLSConfig(cfg); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	cfg, err := newTLSConfig(TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile, ClientAuth: tls.VerifyClientCertIfGiven})
+	if err != nil || cfg.ClientAuth != tls.VerifyClientCertIfGiven {
+		t.Errorf("expected optional client certificates to be kept, got %v, %v", cfg, err)
+	}
+}
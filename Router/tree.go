@@ -0,0 +1,324 @@
+package Router
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// nodeKind identifies what part of a route pattern a node of the routing tree represents.
+// Static nodes hold a literal chunk of the path, param nodes match a single ":name" segment,
+// and catch-all nodes match the remainder of the path for a "*name" segment.
+type nodeKind uint8
+
+const (
+	staticNode nodeKind = iota
+	paramNode
+	catchAllNode
+)
+
+// node is a node of the compressed radix tree used to resolve routes.
+// There is one tree per HTTP method, rooted at an empty static node.
+// Static nodes share common prefixes so that a lookup only walks the bytes of the path once.
+// The prefix field holds the literal chunk for static nodes and the parameter name for param and catch-all nodes.
+// The indices field holds the first byte of every static child, in the same order as the children slice,
+// which allows picking the right child without comparing full prefixes.
+// The params and catchAll fields hold the dynamic children, which are only attached after a "/".
+// Constrained param children are tried in registration order, before the unconstrained one.
+// The constraint field is only set on param nodes declared with a "<...>" constraint.
+// The route field is set when a registered route ends at this node.
+type node struct {
+	kind       nodeKind
+	prefix     string
+	indices    string
+	children   []*node
+	params     []*node
+	catchAll   *node
+	constraint *constraint
+	route      *Route
+}
+
+// param is a single path parameter captured while walking the tree.
+// Parameters are collected in a slice during the lookup so that static hits never allocate.
+type param struct {
+	key   string
+	value string
+}
+
+// longestCommonPrefix returns the length of the longest common prefix of a and b.
+func longestCommonPrefix(a, b string) int {
+	max := len(a)
+	if len(b) < max {
+		max = len(b)
+	}
+	i := 0
+	for i < max && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// insertStatic inserts a literal chunk of a pattern below n and returns the node where the chunk ends.
+// Existing static children sharing a prefix with the chunk are split so that the tree stays compressed.
+func (n *node) insertStatic(chunk string) *node {
+	for chunk != "" {
+		i := strings.IndexByte(n.indices, chunk[0])
+		if i < 0 {
+			child := &node{kind: staticNode, prefix: chunk}
+			n.indices += string(chunk[0])
+			n.children = append(n.children, child)
+			return child
+		}
+
+		child := n.children[i]
+		l := longestCommonPrefix(chunk, child.prefix)
+		if l < len(child.prefix) {
+			tail := *child
+			tail.prefix = child.prefix[l:]
+			*child = node{
+				kind:     staticNode,
+				prefix:   child.prefix[:l],
+				indices:  string(tail.prefix[0]),
+				children: []*node{&tail},
+			}
+		}
+
+		chunk = chunk[l:]
+		n = child
+	}
+	return n
+}
+
+// insert adds the route to the tree rooted at n.
+// The pattern is split into literal chunks, ":name" segments and a trailing "*name" segment,
+// and a node is created or reused for each of them.
+// It returns an error if the pattern is malformed, if it is already registered,
+// or if it is ambiguous with an existing route, such as "/a/:x" and "/a/:y".
+func (n *node) insert(pattern string, rt *Route) error {
+	if pattern == "" || pattern[0] != '/' {
+		return errors.New("path must begin with '/'")
+	}
+
+	for {
+		i := segmentStart(pattern)
+		n = n.insertStatic(pattern[:i])
+		if i == len(pattern) {
+			break
+		}
+
+		pattern = pattern[i:]
+		name, expr, end, err := parseWildcard(pattern)
+		if err != nil {
+			return err
+		}
+
+		if pattern[0] == '*' {
+			if expr != "" {
+				return fmt.Errorf("catch-all %q cannot have a constraint", pattern[:end])
+			}
+			if end != len(pattern) {
+				return fmt.Errorf("catch-all %q must be the last segment of the path", pattern[:end])
+			}
+			if n.catchAll == nil {
+				n.catchAll = &node{kind: catchAllNode, prefix: name}
+			} else if n.catchAll.prefix != name {
+				return fmt.Errorf("catch-all %q conflicts with existing catch-all %q", "*"+name, "*"+n.catchAll.prefix)
+			}
+			n = n.catchAll
+			break
+		}
+
+		if n, err = n.insertParam(name, expr); err != nil {
+			return err
+		}
+		if n.constraint != nil {
+			if rt.constraints == nil {
+				rt.constraints = make(map[string]*constraint)
+			}
+			rt.constraints[name] = n.constraint
+		}
+		pattern = pattern[end:]
+	}
+
+	if n.route != nil {
+		return fmt.Errorf("a handler is already registered for %s", n.route.pattern)
+	}
+	n.route = rt
+	return nil
+}
+
+// insertParam returns the param child of n for the given name and constraint expression,
+// creating it if needed. Two params at the same position must differ by their constraint:
+// "/a/:x" and "/a/:y" are ambiguous, while "/a/:id<int>" and "/a/:slug" are not.
+// Several constrained params are allowed at a position, such as "/a/:id<int>" and "/a/:slug<uuid>".
+// They are tried in registration order, so when two constraints accept the same value,
+// such as "/a/:id<int>" and "/a/:n<[0-9]+>", the param registered first wins.
+// The unconstrained param is kept last so that it is only tried when no constraint matches.
+func (n *node) insertParam(name, expr string) (*node, error) {
+	for _, child := range n.params {
+		if child.constraintExpr() != expr {
+			continue
+		}
+		if child.prefix != name {
+			return nil, fmt.Errorf("parameter %q conflicts with existing parameter %q", wildcardString(name, expr), wildcardString(child.prefix, expr))
+		}
+		return child, nil
+	}
+
+	child := &node{kind: paramNode, prefix: name}
+	if expr != "" {
+		c, err := newConstraint(expr)
+		if err != nil {
+			return nil, err
+		}
+		child.constraint = c
+	}
+
+	i := len(n.params)
+	if expr != "" {
+		for i > 0 && n.params[i-1].constraint == nil {
+			i--
+		}
+	}
+	n.params = append(n.params, nil)
+	copy(n.params[i+1:], n.params[i:])
+	n.params[i] = child
+	return child, nil
+}
+
+// constraintExpr returns the constraint expression of a param node, or an empty string if it has none.
+func (n *node) constraintExpr() string {
+	if n.constraint == nil {
+		return ""
+	}
+	return n.constraint.expr
+}
+
+// wildcardString formats a parameter name and its constraint expression as written in a route pattern.
+func wildcardString(name, expr string) string {
+	if expr == "" {
+		return ":" + name
+	}
+	return ":" + name + "<" + expr + ">"
+}
+
+// parseWildcard parses the ":name", ":name<expr>" or "*name" segment at the start of the pattern.
+// It returns the name, the constraint expression if any, and the index where the segment ends.
+// The constraint expression may contain "/" and nested angle brackets, which are matched by depth.
+func parseWildcard(pattern string) (name, expr string, end int, err error) {
+	end = len(pattern)
+	for i := 1; i < len(pattern); i++ {
+		if pattern[i] == '/' {
+			end = i
+			break
+		}
+		if pattern[i] != '<' {
+			continue
+		}
+
+		name = pattern[1:i]
+		depth := 0
+		closing := -1
+		for j := i; j < len(pattern) && closing < 0; j++ {
+			switch pattern[j] {
+			case '<':
+				depth++
+			case '>':
+				depth--
+				if depth == 0 {
+					closing = j
+				}
+			}
+		}
+		if closing < 0 {
+			return "", "", 0, fmt.Errorf("unterminated constraint in %q", pattern)
+		}
+		expr = pattern[i+1 : closing]
+		end = closing + 1
+		if expr == "" {
+			return "", "", 0, fmt.Errorf("empty constraint in %q", pattern[:end])
+		}
+		if end < len(pattern) && pattern[end] != '/' {
+			return "", "", 0, fmt.Errorf("constraint in %q must end the path segment", pattern)
+		}
+		break
+	}
+
+	if expr == "" {
+		name = pattern[1:end]
+	}
+	if name == "" {
+		return "", "", 0, fmt.Errorf("wildcard %q must have a non-empty name", pattern[:1])
+	}
+	if strings.ContainsAny(name, ":*") {
+		return "", "", 0, fmt.Errorf("only one wildcard per path segment is allowed, got %q", pattern[:end])
+	}
+	return name, expr, end, nil
+}
+
+// segmentStart returns the index of the first ":" or "*" that starts a path segment in the pattern,
+// or the length of the pattern if it only contains literal segments.
+func segmentStart(pattern string) int {
+	for i := 0; i < len(pattern); i++ {
+		if (pattern[i] == ':' || pattern[i] == '*') && i > 0 && pattern[i-1] == '/' {
+			return i
+		}
+	}
+	return len(pattern)
+}
+
+// find resolves the remaining path below n and returns the matched route, or nil if none matches.
+// Static children are tried first, then the param children, then the catch-all child,
+// backtracking when a more specific branch does not lead to a registered route.
+// This gives a deterministic priority that does not depend on the registration order:
+// "/users/me" always beats "/users/:id", which always beats "/users/*rest".
+// Captured parameters are appended to ps and removed again when a branch is abandoned.
+func (n *node) find(path string, ps *[]param) *Route {
+	if path == "" {
+		if n.route != nil {
+			return n.route
+		}
+		if n.catchAll != nil && n.catchAll.route != nil {
+			*ps = append(*ps, param{n.catchAll.prefix, ""})
+			return n.catchAll.route
+		}
+		return nil
+	}
+
+	if i := strings.IndexByte(n.indices, path[0]); i >= 0 {
+		child := n.children[i]
+		if strings.HasPrefix(path, child.prefix) {
+			if rt := child.find(path[len(child.prefix):], ps); rt != nil {
+				return rt
+			}
+		}
+	}
+
+	if len(n.params) > 0 {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if end > 0 {
+			value := path[:end]
+			for _, child := range n.params {
+				if child.constraint != nil && !child.constraint.match(value) {
+					continue
+				}
+				mark := len(*ps)
+				*ps = append(*ps, param{child.prefix, value})
+				if rt := child.find(path[end:], ps); rt != nil {
+					return rt
+				}
+				*ps = (*ps)[:mark]
+			}
+		}
+	}
+
+	if n.catchAll != nil && n.catchAll.route != nil {
+		*ps = append(*ps, param{n.catchAll.prefix, path})
+		return n.catchAll.route
+	}
+
+	return nil
+}
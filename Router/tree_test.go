@@ -0,0 +1,290 @@
+package Router
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+)
+
+// noop is the handler of the routes registered by the tests and benchmarks.
+func noop(*context.Context) {}
+
+// lookupTree resolves the route registered for the method and path in the routing trees of the router.
+func lookupTree(r *Router, method, path string) (*Route, []param) {
+	root := r.trees[method]
+	if root == nil {
+		return nil, nil
+	}
+	var ps []param
+	return root.find(path, &ps), ps
+}
+
+func TestFindPriority(t *testing.T) {
+	r := NewRouter()
+	for _, pattern := range []string{
+		"/users/me",
+		"/users/:id",
+		"/users/*rest",
+		"/users/:id/posts",
+		"/users/me/settings",
+		"/files/*path",
+		"/files/static/logo.png",
+		"/a/:x/b",
+		"/a/:x/:y",
+		"/items/:slug",
+		"/items/:id<int>",
+		"/items/:ref<uuid>",
+		"/items/:code<[0-9a-f]+>",
+		"/",
+	} {
+		r.GET(pattern, noop)
+	}
+
+	tests := []struct {
+		path    string
+		pattern string
+		params  []param
+	}{
+		{"/users/me", "/users/me", nil},
+		{"/users/42", "/users/:id", []param{{"id", "42"}}},
+		{"/users/42/posts", "/users/:id/posts", []param{{"id", "42"}}},
+		{"/users/me/settings", "/users/me/settings", nil},
+		// "/users/me" has no "posts" child: the lookup backtracks to the ":id" param.
+		{"/users/me/posts", "/users/:id/posts", []param{{"id", "me"}}},
+		// Neither "me" nor ":id" lead to a route: the lookup backtracks to the catch-all.
+		{"/users/me/unknown", "/users/*rest", []param{{"rest", "me/unknown"}}},
+		{"/users/42/posts/1", "/users/*rest", []param{{"rest", "42/posts/1"}}},
+		{"/users/", "/users/*rest", []param{{"rest", ""}}},
+		{"/files/static/logo.png", "/files/static/logo.png", nil},
+		{"/files/static/other.png", "/files/*path", []param{{"path", "static/other.png"}}},
+		{"/a/1/b", "/a/:x/b", []param{{"x", "1"}}},
+		{"/a/1/c", "/a/:x/:y", []param{{"x", "1"}, {"y", "c"}}},
+		// Constrained params are tried in registration order, before the unconstrained one.
+		{"/items/42", "/items/:id<int>", []param{{"id", "42"}}},
+		{"/items/0b9a2c4e-7f1d-4e8a-9c3b-5d6e7f8a9b0c", "/items/:ref<uuid>", []param{{"ref", "0b9a2c4e-7f1d-4e8a-9c3b-5d6e7f8a9b0c"}}},
+		{"/items/ff", "/items/:code<[0-9a-f]+>", []param{{"code", "ff"}}},
+		{"/items/hello", "/items/:slug", []param{{"slug", "hello"}}},
+		{"/", "/", nil},
+		{"/unknown", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rt, ps := lookupTree(r, "GET", tt.path)
+			if tt.pattern == "" {
+				if rt != nil {
+					t.Fatalf("expected no match, got %s", rt.pattern)
+				}
+				return
+			}
+			if rt == nil {
+				t.Fatalf("expected %s, got no match", tt.pattern)
+			}
+			if rt.pattern != tt.pattern {
+				t.Errorf("expected %s, got %s", tt.pattern, rt.pattern)
+			}
+			if !slices.Equal(ps, tt.params) {
+				t.Errorf("expected params %v, got %v", tt.params, ps)
+			}
+		})
+	}
+}
+
+func TestFindDoesNotDependOnRegistrationOrder(t *testing.T) {
+	patterns := []string{"/users/me", "/users/:id", "/users/*rest"}
+	for i := range patterns {
+		r := NewRouter()
+		order := append(slices.Clone(patterns[i:]), patterns[:i]...)
+		for _, pattern := range order {
+			r.GET(pattern, noop)
+		}
+		for path, want := range map[string]string{"/users/me": "/users/me", "/users/1": "/users/:id", "/users/1/2": "/users/*rest"} {
+			if rt, _ := lookupTree(r, "GET", path); rt == nil || rt.pattern != want {
+				t.Errorf("registration order %v: %s did not match %s", order, path, want)
+			}
+		}
+	}
+}
+
+func TestInsertConflicts(t *testing.T) {
+	tests := []struct {
+		existing string
+		pattern  string
+		conflict bool
+	}{
+		{"/a/:x", "/a/:y", true},
+		{"/a/:id<int>", "/a/:slug", false},
+		{"/a/:id<int>", "/a/:id<int>/b", false},
+		{"/a/:id<int>", "/a/:n<[0-9]+>", false},
+		{"/a/:id<int>", "/a/:n<uuid>", false},
+		{"/a/:id<int>", "/a/:n<int>", true},
+		{"/a/*x", "/a/*y", true},
+		{"/a/:x", "/a/:x", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.existing+" "+tt.pattern, func(t *testing.T) {
+			root := &node{}
+			if err := root.insert(tt.existing, &Route{pattern: tt.existing}); err != nil {
+				t.Fatal(err)
+			}
+			err := root.insert(tt.pattern, &Route{pattern: tt.pattern})
+			if tt.conflict && err == nil {
+				t.Errorf("expected %s to conflict with %s", tt.pattern, tt.existing)
+			}
+			if !tt.conflict && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFindStaticDoesNotAllocate(t *testing.T) {
+	r := benchRouter()
+	allocs := testing.AllocsPerRun(100, func() {
+		lookupTree(r, "GET", "/static/res150/index")
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocation for a static route, got %v", allocs)
+	}
+}
+
+// benchRoutes is the number of resources of the routes registered by the benchmarks,
+// each resource having a static, two param and a catch-all route.
+const benchRoutes = 300
+
+// benchPatterns returns the route patterns registered by the benchmarks.
+func benchPatterns() []string {
+	var patterns []string
+	for i := 0; i < benchRoutes; i++ {
+		patterns = append(patterns,
+			fmt.Sprintf("/static/res%d/index", i),
+			fmt.Sprintf("/api/res%d/:id", i),
+			fmt.Sprintf("/api/res%d/:id/items/:item", i),
+			fmt.Sprintf("/files/res%d/*path", i),
+		)
+	}
+	return patterns
+}
+
+// benchRouter returns a router with the routes of benchPatterns.
+func benchRouter() *Router {
+	r := NewRouter()
+	for _, pattern := range benchPatterns() {
+		r.GET(pattern, noop)
+	}
+	return r
+}
+
+// linearRoute is a dynamic route of linearRouter.
+type linearRoute struct {
+	method  string
+	pattern string
+	handler context.HandlerFunc
+}
+
+// linearRouter reproduces the lookup the radix tree replaced, used as the baseline of the benchmarks:
+// a map of the static routes by method and path, then a linear scan of the dynamic routes with matchPattern.
+type linearRouter struct {
+	handlers      map[string]map[string]context.HandlerFunc
+	dynamicRoutes []linearRoute
+}
+
+// newLinearRouter returns a linearRouter with the routes of benchPatterns.
+func newLinearRouter() *linearRouter {
+	lr := &linearRouter{handlers: map[string]map[string]context.HandlerFunc{"GET": {}}}
+	for _, pattern := range benchPatterns() {
+		if strings.ContainsAny(pattern, ":*") {
+			lr.dynamicRoutes = append(lr.dynamicRoutes, linearRoute{"GET", pattern, noop})
+		} else {
+			lr.handlers["GET"][pattern] = noop
+		}
+	}
+	return lr
+}
+
+// lookup resolves the handler of the method and path as the previous router did.
+func (lr *linearRouter) lookup(method, path string) (context.HandlerFunc, map[string]string) {
+	if handler, ok := lr.handlers[method][path]; ok {
+		return handler, nil
+	}
+	for _, rt := range lr.dynamicRoutes {
+		if rt.method != method {
+			continue
+		}
+		if params, ok := matchPattern(rt.pattern, path); ok {
+			return rt.handler, params
+		}
+	}
+	return nil, nil
+}
+
+// matchPattern is the pattern matching of the previous router, splitting the pattern and the path on each call.
+func matchPattern(pattern, path string) (map[string]string, bool) {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+
+	params := make(map[string]string)
+	for i := 0; i < len(patternParts); i++ {
+		if i >= len(pathParts) {
+			return nil, false
+		}
+		pp := patternParts[i]
+		pv := pathParts[i]
+		if strings.HasPrefix(pp, ":") {
+			params[pp[1:]] = pv
+		} else if strings.HasPrefix(pp, "*") {
+			params[pp[1:]] = strings.Join(pathParts[i:], "/")
+			return params, true
+		} else if pp != pv {
+			return nil, false
+		}
+	}
+	if len(pathParts) != len(patternParts) {
+		return nil, false
+	}
+	return params, true
+}
+
+// benchPaths are the request paths looked up by the benchmarks, hitting routes of the last registered resource,
+// which is the worst case of the linear scan.
+var benchPaths = []struct {
+	name string
+	path string
+}{
+	{"static", fmt.Sprintf("/static/res%d/index", benchRoutes-1)},
+	{"param", fmt.Sprintf("/api/res%d/42", benchRoutes-1)},
+	{"params", fmt.Sprintf("/api/res%d/42/items/7", benchRoutes-1)},
+	{"catchall", fmt.Sprintf("/files/res%d/css/site/main.css", benchRoutes-1)},
+}
+
+func BenchmarkTreeLookup(b *testing.B) {
+	r := benchRouter()
+	for _, bp := range benchPaths {
+		if rt, _ := lookupTree(r, "GET", bp.path); rt == nil {
+			b.Fatalf("no route for %s", bp.path)
+		}
+		b.Run(bp.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				lookupTree(r, "GET", bp.path)
+			}
+		})
+	}
+}
+
+func BenchmarkLinearLookup(b *testing.B) {
+	lr := newLinearRouter()
+	for _, bp := range benchPaths {
+		if handler, _ := lr.lookup("GET", bp.path); handler == nil {
+			b.Fatalf("no route for %s", bp.path)
+		}
+		b.Run(bp.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				lr.lookup("GET", bp.path)
+			}
+		})
+	}
+}
@@ -1,38 +1,159 @@
 package Router
 
 import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	context "github.com/ines-mgg/LetsGoBack/Context"
 	middleware "github.com/ines-mgg/LetsGoBack/Middleware"
 )
 
-// dynamicRoute represents a route that can have dynamic parameters.
+// Route represents a route registered on the router.
 // It contains the HTTP method, the route pattern, and the handler function.
 // The pattern can include parameters prefixed with ":" for single parameters or "*" for catch-all parameters.
 // The handler function is a context.HandlerFunc that will be executed when the route is matched.
-// The dynamic routes are stored in a slice of dynamicRoute structs within the Router struct.
+// The middlewares are the ones given at registration for this route only, and group is the route group
+// the route was registered through, if any.
+// The chain is the handler wrapped with the global, group and route middlewares, built once by Router.Compile.
+// The host is the host pattern the route is restricted to, or empty for routes of the default host.
+// The name is optional and set through the Name method, to build the URL of the route with Router.URL.
+// The router field points back to the Router the route is registered on.
+// The constraints map holds the constraints of the constrained parameters of the pattern, keyed by parameter name,
+// so that Router.URL only builds URLs the route matches.
+// Routes are stored in the routing tree of their method and, in registration order, in the routes slice of the Router.
 // This allows the router to handle routes with dynamic segments, such as "/users/:id" or "/files/*filepath".
-type dynamicRoute struct {
-	method  string
-	pattern string
-	handler context.HandlerFunc
+// A Route is returned by the registration methods, such as GET or Handle, so that it can be named;
+// its fields are not exported since a route cannot be changed once registered.
+type Route struct {
+	method      string
+	pattern     string
+	handler     context.HandlerFunc
+	middlewares []middleware.Middleware
+	group       *routeGroup
+	chain       context.HandlerFunc
+	host        string
+	name        string
+	router      *Router
+	constraints map[string]*constraint
 }
 
 // Router is the main structure that holds all the routes and their handlers.
-// It contains a map of HTTP methods to their corresponding routes and handlers.
-// The Handlers map is a nested map where the first key is the HTTP method (e.g., "GET", "POST"),
-// and the second key is the route path (e.g., "/users/:id").
-// The Handlers map allows for quick lookup of handlers based on the HTTP method and route path.
-// The Router also maintains a slice of dynamicRoute structs to handle routes with dynamic parameters.
+// It contains one routing tree per HTTP method, keyed by the method name (e.g., "GET", "POST").
+// Each tree is a compressed radix tree that resolves static, ":param" and "*catchall" segments
+// in a single pass over the request path, without allocating for static routes.
+// The routes slice keeps every registered route in registration order, for listing purposes.
+// The hosts slice holds the routes registered through Host groups, ordered by matching priority.
+// The names map indexes the named routes by their name, to build URLs from route definitions.
 // The Middlewares slice contains middleware functions that can be applied to all routes.
 // The NotFoundHandler is a context.HandlerFunc that will be called when no route matches the request.
 // The MethodNotAllowedHandler is a context.HandlerFunc that will be called when the method is not allowed for a specific route.
+// The ErrorHandler renders the errors returned by context.HandlerFuncE handlers, and defaults to context.DefaultErrorHandler.
+// It can map application errors, such as sql.ErrNoRows, to the HTTP errors of the context package.
+// HandleMethodNotAllowed enables the 405 Method Not Allowed responses, with the Allow header listing the allowed methods.
+// HandleOPTIONS enables automatic answers to OPTIONS requests for paths without a dedicated OPTIONS route.
+// Both options are enabled by NewRouter.
+// RedirectTrailingSlash redirects requests to the path with or without a trailing slash when only that one matches a route,
+// for example "/users/" to "/users".
+// RedirectFixedPath redirects requests to the cleaned path, without repeated slashes nor "." and ".." elements,
+// matched case-insensitively against the static segments of the routes, for example "//Users/../users" to "/users".
+// Redirects use 301 Moved Permanently for GET and HEAD requests and 308 Permanent Redirect for the other methods,
+// and keep the query string.
+// UseRawPath matches routes against the escaped path of the request, when it has one, instead of the unescaped path,
+// so that an escaped "/" such as in "/files/a%2Fb" stays inside a single parameter.
+// UnescapePathValues unescapes the parameter values captured from the escaped path when UseRawPath is set.
+// RedirectTrailingSlash and UnescapePathValues are enabled by NewRouter.
+// UseMiddlewaresOnNoRoute wraps the not found and method not allowed handlers with the global middlewares,
+// so that CORS headers, request IDs, recovery and access logs also apply to 404 and 405 responses.
+// DevMode enables development behaviors, such as reporting in the logs the responses written twice for a request.
+// It must not be enabled in production.
+// ProblemDetails renders the responses of the context Error helpers and of the ErrorHandler as RFC 9457
+// application/problem+json documents, with the request ID as instance, instead of {"error": message} JSON bodies.
+// BodyConfig limits the size of the request bodies read by the Bind and BindBody methods of the context,
+// 10 MB by default, and can reject the unknown fields of JSON bodies.
+// Views is the HTML view engine rendering the templates of the HTML method of the context, see NewViews.
+// It is loaded by Compile, and reloads the templates when their files change if DevMode is set,
+// which walks the files of the views on every render.
+// The compiled flag is set by Compile, after which routes, route names, hosts and middlewares can no longer be added.
+// The optionsHandler is the automatic OPTIONS handler wrapped with the global middlewares, built by Compile.
+// The notFoundHandler and methodNotAllowedHandler run the NotFoundHandler and MethodNotAllowedHandler read at request time,
+// falling back to the net/http defaults, and notFoundChain and methodNotAllowedChain wrap them with the global middlewares.
+// They are built once by Compile, so that serving a 404 or 405 response does not allocate a handler.
+// The servers slice holds the servers started by the router, stopped together by Shutdown,
+// which then runs the shutdownHooks and closes shutdownDone, with shutdownErr holding its result.
 type Router struct {
-	Handlers      map[string]map[string]context.HandlerFunc
-	DynamicRoutes []dynamicRoute
-	Middlewares   []middleware.Middleware
+	trees       map[string]*node
+	routes      []*Route
+	hosts       []*hostRoutes
+	names       map[string]*Route
+	Middlewares []middleware.Middleware
 
 	NotFoundHandler         context.HandlerFunc
 	MethodNotAllowedHandler context.HandlerFunc
+	ErrorHandler            context.ErrorHandlerFunc
+
+	HandleMethodNotAllowed  bool
+	HandleOPTIONS           bool
+	RedirectTrailingSlash   bool
+	RedirectFixedPath       bool
+	UseRawPath              bool
+	UnescapePathValues      bool
+	UseMiddlewaresOnNoRoute bool
+	DevMode                 bool
+	ProblemDetails          bool
+	BodyConfig              context.BodyConfig
+	Views                   *Views
+
+	compileOnce             sync.Once
+	compiled                atomic.Bool
+	optionsHandler          context.HandlerFunc
+	notFoundHandler         context.HandlerFunc
+	methodNotAllowedHandler context.HandlerFunc
+	notFoundChain           context.HandlerFunc
+	methodNotAllowedChain   context.HandlerFunc
+
+	serverMu      sync.Mutex
+	servers       []*http.Server
+	shutdownHooks []func()
+	shuttingDown  bool
+	shutdownDone  chan struct{}
+	shutdownErr   error
+}
+
+// ServerConfig holds the configuration of the http.Server started by Router.ListenWithConfig.
+// ReadTimeout, ReadHeaderTimeout, WriteTimeout, IdleTimeout and MaxHeaderBytes are passed as is to the http.Server,
+// where a zero value means no timeout, or the net/http default for MaxHeaderBytes.
+// HandleSignals shuts the server down gracefully when the process receives SIGINT or SIGTERM,
+// giving active requests ShutdownTimeout to complete, or 30 seconds if it is zero.
+// H2C enables HTTP/2 over cleartext connections, in addition to HTTP/1, for services talking to each other
+// behind a mesh or a proxy terminating TLS. HTTP/2 is always enabled for TLS servers.
+type ServerConfig struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+
+	HandleSignals   bool
+	ShutdownTimeout time.Duration
+
+	H2C bool
+}
+
+// TLSConfig holds the TLS settings of the server started by Router.ListenTLSWithConfig.
+// CertFile and KeyFile are the paths of the PEM encoded certificate and private key of the server,
+// which are reloaded automatically when they change on disk.
+// ClientCAFile is the path of the PEM encoded authorities used to verify client certificates, for mutual TLS.
+// ClientAuth is the client certificate policy, which defaults to tls.RequireAndVerifyClientCert
+// when ClientCAFile is set; tls.VerifyClientCertIfGiven makes client certificates optional.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+
+	ClientCAFile string
+	ClientAuth   tls.ClientAuthType
 }
 
 // routeGroup represents a group of routes with a common prefix and shared middlewares.
@@ -42,8 +163,13 @@ type Router struct {
 // The router field is a pointer to the parent Router, allowing the group to add routes to it.
 // The middlewares field is a slice of middleware.Middleware that can be used to apply common functionality
 // to all routes in the group, such as logging, authentication, or error handling.
+// The parent field points to the group this group was created from, if any,
+// so that the middlewares of the parent groups also apply to its routes.
+// The host field is set for groups created by Router.Host, and restricts their routes to the matching hosts.
 type routeGroup struct {
 	prefix      string
 	router      *Router
+	parent      *routeGroup
+	host        *hostRoutes
 	middlewares []middleware.Middleware
 }
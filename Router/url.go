@@ -0,0 +1,88 @@
+package Router
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Name sets the name of the route, so that its URL can be built with Router.URL.
+// It panics if the name is empty or already used by another route of the router,
+// or if the router is already compiled, since the names are then read concurrently by URL.
+// It returns the route itself, allowing the call to be chained with the registration:
+//
+//	r.GET("/users/:id", showUser).Name("user.show")
+func (rt *Route) Name(name string) *Route {
+	if rt.router.compiled.Load() {
+		panic(fmt.Sprintf("router: cannot name %s %s: routes cannot be named once the router is compiled", rt.method, rt.pattern))
+	}
+	if name == "" {
+		panic(fmt.Sprintf("router: cannot name %s %s: name must not be empty", rt.method, rt.pattern))
+	}
+	if other, ok := rt.router.names[name]; ok && other != rt {
+		panic(fmt.Sprintf("router: cannot name %s %s: name %q is already used by %s %s", rt.method, rt.pattern, name, other.method, other.pattern))
+	}
+
+	if rt.name != "" {
+		delete(rt.router.names, rt.name)
+	}
+	rt.name = name
+	rt.router.names[name] = rt
+	return rt
+}
+
+// URL builds the URL path of the named route, substituting its parameters with the given values.
+// Values of ":param" segments are escaped as a single path segment,
+// while values of "*catchall" segments are escaped segment by segment so that their slashes are kept.
+// It returns an error if no route has this name, if a parameter of the route is missing from params,
+// or if a value does not satisfy the constraint of its parameter, such as "abc" for ":id<int>",
+// since the URL would not match the route.
+// Usage example:
+//
+//	r.GET("/api/v1/users/:id", showUser).Name("user.show")
+//	path, err := r.URL("user.show", map[string]string{"id": "42"}) // "/api/v1/users/42"
+func (r *Router) URL(name string, params map[string]string) (string, error) {
+	rt, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("no route named %q", name)
+	}
+
+	var b strings.Builder
+	pattern := rt.pattern
+	for {
+		i := segmentStart(pattern)
+		b.WriteString(pattern[:i])
+		if i == len(pattern) {
+			break
+		}
+
+		pattern = pattern[i:]
+		key, _, end, err := parseWildcard(pattern)
+		if err != nil {
+			return "", err
+		}
+		value, ok := params[key]
+		if !ok {
+			return "", fmt.Errorf("missing parameter %q for route %q", key, name)
+		}
+
+		if pattern[0] == '*' {
+			segments := strings.Split(value, "/")
+			for j, segment := range segments {
+				segments[j] = url.PathEscape(segment)
+			}
+			b.WriteString(strings.Join(segments, "/"))
+		} else {
+			if value == "" {
+				return "", fmt.Errorf("empty parameter %q for route %q", key, name)
+			}
+			if c := rt.constraints[key]; c != nil && !c.match(value) {
+				return "", fmt.Errorf("parameter %q of route %q does not satisfy constraint <%s>: %q", key, name, c.expr, value)
+			}
+			b.WriteString(url.PathEscape(value))
+		}
+		pattern = pattern[end:]
+	}
+
+	return b.String(), nil
+}
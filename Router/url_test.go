@@ -0,0 +1,41 @@
+package Router
+
+import "testing"
+
+func TestURL(t *testing.T) {
+	r := NewRouter()
+	r.GET("/users/:id<int>", noop).Name("user.show")
+	r.GET("/files/:name<[a-z0-9-]+>/*path", noop).Name("file")
+	r.GET("/posts/:slug", noop).Name("post")
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		want   string
+		err    bool
+	}{
+		{"user.show", map[string]string{"id": "42"}, "/users/42", false},
+		{"user.show", map[string]string{"id": "abc"}, "", true},
+		{"user.show", nil, "", true},
+		{"file", map[string]string{"name": "docs", "path": "a b/c.txt"}, "/files/docs/a%20b/c.txt", false},
+		{"file", map[string]string{"name": "Docs", "path": "c.txt"}, "", true},
+		{"post", map[string]string{"slug": "a/b"}, "/posts/a%2Fb", false},
+		{"post", map[string]string{"slug": ""}, "", true},
+		{"unknown", nil, "", true},
+	}
+	for _, tt := range tests {
+		got, err := r.URL(tt.name, tt.params)
+		if tt.err {
+			if err == nil {
+				t.Errorf("URL(%q, %v): expected an error, got %q", tt.name, tt.params, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("URL(%q, %v) = %q, %v, want %q", tt.name, tt.params, got, err, tt.want)
+		}
+		if rt, _ := findRoute(r.trees, "GET", got); rt == nil || rt.name != tt.name {
+			t.Errorf("URL(%q, %v) = %q does not match the route", tt.name, tt.params, got)
+		}
+	}
+}
@@ -2,28 +2,121 @@ package Router
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+	middleware "github.com/ines-mgg/LetsGoBack/Middleware"
 )
 
-// PrintRoutes prints all registered routes in the router.
-// It iterates through the Handlers map and the DynamicRoutes slice,
-// printing the HTTP method and path for each route.
-func (r *Router) PrintRoutes() {
-	fmt.Println("Registered routes:")
-	for method, paths := range r.Handlers {
-		for path := range paths {
-			fmt.Printf("%s\t%s\n", method, path)
+// splitHandlers splits the handlers given to a registration method into route middlewares and the final handler.
+// The last element must be a context.HandlerFunc (or a plain func(*context.Context)),
+// or a context.HandlerFuncE (or a plain func(*context.Context) error) whose errors go to the error handler of the router,
+// and every element before it must be a middleware.Middleware (or a plain func(context.HandlerFunc) context.HandlerFunc).
+// It returns an error describing the first element that does not fit, including nil handlers and middlewares.
+func (r *Router) splitHandlers(handlers []any) ([]middleware.Middleware, context.HandlerFunc, error) {
+	if len(handlers) == 0 {
+		return nil, nil, errors.New("a handler is required")
+	}
+
+	var handler context.HandlerFunc
+	switch h := handlers[len(handlers)-1].(type) {
+	case context.HandlerFunc:
+		handler = h
+	case func(*context.Context):
+		handler = h
+	case context.HandlerFuncE:
+		if h != nil {
+			handler = r.handlerE(h)
+		}
+	case func(*context.Context) error:
+		if h != nil {
+			handler = r.handlerE(h)
+		}
+	default:
+		return nil, nil, fmt.Errorf("the last handler must be a context.HandlerFunc or a context.HandlerFuncE, got %T", h)
+	}
+	if handler == nil {
+		return nil, nil, errors.New("the handler must not be nil")
+	}
+
+	mws := make([]middleware.Middleware, 0, len(handlers)-1)
+	for i, h := range handlers[:len(handlers)-1] {
+		var mw middleware.Middleware
+		switch m := h.(type) {
+		case middleware.Middleware:
+			mw = m
+		case func(context.HandlerFunc) context.HandlerFunc:
+			mw = m
+		default:
+			return nil, nil, fmt.Errorf("handler %d must be a middleware.Middleware, got %T", i, h)
+		}
+		if mw == nil {
+			return nil, nil, fmt.Errorf("middleware %d must not be nil", i)
 		}
+		mws = append(mws, mw)
 	}
+	return mws, handler, nil
+}
 
-	for _, dr := range r.DynamicRoutes {
-		fmt.Printf("%s\t%s\n", dr.method, dr.pattern)
+// checkHandler reports an error if the handler or one of the middlewares given to Handle is nil,
+// with the same messages as splitHandlers.
+func checkHandler(handler context.HandlerFunc, mws []middleware.Middleware) error {
+	if handler == nil {
+		return errors.New("the handler must not be nil")
+	}
+	for i, mw := range mws {
+		if mw == nil {
+			return fmt.Errorf("middleware %d must not be nil", i)
+		}
+	}
+	return nil
+}
+
+// handlerE adapts a handler returning an error to a context.HandlerFunc.
+// The error returned by the handler, if any, is rendered by the error handler of the router, built by Compile.
+func (r *Router) handlerE(h context.HandlerFuncE) context.HandlerFunc {
+	return func(c *context.Context) {
+		if err := h(c); err != nil {
+			r.handleError(c, err)
+		}
+	}
+}
+
+// chain wraps the handler with the given middlewares.
+// Middlewares are applied in reverse order so that the first one in the slice runs first.
+// The next handler given to each middleware is skipped once the context is aborted,
+// so that a middleware calling Abort stops the chain even if it, or a middleware before it, calls next.
+func chain(handler context.HandlerFunc, mws []middleware.Middleware) context.HandlerFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](skipAborted(handler))
+	}
+	return handler
+}
+
+// skipAborted wraps the handler so that it does not run once the context is aborted.
+func skipAborted(next context.HandlerFunc) context.HandlerFunc {
+	return func(c *context.Context) {
+		if c.IsAborted() {
+			return
+		}
+		next(c)
+	}
+}
+
+// PrintRoutes prints all registered routes in the router.
+// It iterates through the registered routes in registration order,
+// printing the HTTP method and path for each route, prefixed by its host pattern if it has one.
+func (r *Router) PrintRoutes() {
+	fmt.Println("Registered routes:")
+	for _, rt := range r.routes {
+		fmt.Printf("%s\t%s%s\n", rt.method, rt.host, rt.pattern)
 	}
 }
 
 // WriteRoutesToJsonFile writes all registered routes to a JSON file.
-// It creates a slice of routeInfo structs, each containing the HTTP method and path.
+// It creates a slice of routeInfo structs, each containing the HTTP method, the host pattern if any, and path.
 // The routes are then marshaled into JSON format and written to the specified file.
 // The filename is appended with ".json" to indicate the file format.
 // If there is an error during marshaling or file writing, it returns the error.
@@ -31,24 +124,17 @@ func (r *Router) PrintRoutes() {
 func (r *Router) WriteRoutesToJsonFile(filename string) error {
 	type routeInfo struct {
 		Method string `json:"method"`
+		Host   string `json:"host,omitempty"`
 		Path   string `json:"path"`
 	}
 
 	var routes []routeInfo
 
-	for method, paths := range r.Handlers {
-		for path := range paths {
-			routes = append(routes, routeInfo{
-				Method: method,
-				Path:   path,
-			})
-		}
-	}
-
-	for _, dr := range r.DynamicRoutes {
+	for _, rt := range r.routes {
 		routes = append(routes, routeInfo{
-			Method: dr.method,
-			Path:   dr.pattern,
+			Method: rt.method,
+			Host:   rt.host,
+			Path:   rt.pattern,
 		})
 	}
 
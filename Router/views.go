@@ -0,0 +1,227 @@
+package Router
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Views is the HTML view engine of the router, rendering the html/template files of a directory or of an fs.FS,
+// such as an embed.FS, with the HTML method of the context.
+// Templates are named after their path relative to the root of the views, without the extension,
+// for example "admin/users" for the file "admin/users.html".
+// The files of the "layouts" and "partials" directories are shared by every page:
+// partials are included with {{template "partials/header" .}}, and the Layout, when set, wraps every page,
+// whose content it includes with {{template "content" .}}. Pages can override the {{block}} of their layout
+// with {{define}}, such as the title of the page.
+// Every template can use the functions added with Funcs, and the url function building the URL of a named route
+// from pairs of parameter names and values, such as {{url "user.show" "id" .ID}}.
+// Views are assigned to Router.Views, and loaded by Router.Compile, which panics if they cannot be parsed.
+// When Router.DevMode is set, the files are checked before each render, and reloaded when they changed,
+// so that templates can be edited without restarting the server.
+// The check walks the whole file system of the views and reads the modification time of every file on each render,
+// and a change reparses every template, so its cost grows with the number of files: it is meant for development only.
+// The Extension is the extension of the template files, ".html" by default, and other files are ignored.
+// The fsys field is the file system the templates are read from, and urls the router building the URLs of the url function.
+// The templates map holds the template set of each page, and the shared set for layouts and partials,
+// which can also be rendered on their own, for example to send HTML fragments.
+// The modTimes map holds the modification times of the loaded files, compared with the files on disk to detect changes.
+type Views struct {
+	Extension string
+	Layout    string
+
+	fsys   fs.FS
+	funcs  template.FuncMap
+	urls   *Router
+	reload bool
+
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+	modTimes  map[string]time.Time
+}
+
+// NewViews creates a view engine rendering the templates of the given directory.
+// Usage example:
+//
+//	r.Views = router.NewViews("templates").Funcs(template.FuncMap{"upper": strings.ToUpper})
+//	r.Views.Layout = "layouts/base"
+func NewViews(dir string) *Views {
+	return NewViewsFS(os.DirFS(dir))
+}
+
+// NewViewsFS creates a view engine rendering the templates of the given file system, such as an embed.FS.
+// Embedded templates are usually stored in a subdirectory, which fs.Sub makes the root of the views:
+//
+//	//go:embed templates
+//	var templates embed.FS
+//
+//	sub, _ := fs.Sub(templates, "templates")
+//	r.Views = router.NewViewsFS(sub)
+func NewViewsFS(fsys fs.FS) *Views {
+	return &Views{
+		Extension: ".html",
+		fsys:      fsys,
+		funcs:     template.FuncMap{},
+	}
+}
+
+// Funcs adds the given functions to the templates, replacing the functions of the same name.
+// Functions must be added before the views are loaded, and the url function can be replaced.
+// It returns the views themselves, allowing the call to be chained with the creation of the views.
+func (v *Views) Funcs(funcs template.FuncMap) *Views {
+	maps.Copy(v.funcs, funcs)
+	return v
+}
+
+// Load parses every template of the views, replacing the templates already loaded.
+// It is called by Router.Compile, but can be called beforehand to report parse errors at startup.
+// It returns an error if a template cannot be read or parsed, or if the Layout does not exist,
+// in which case the templates already loaded are kept.
+func (v *Views) Load() error {
+	modTimes, err := v.scan()
+	if err != nil {
+		return err
+	}
+	return v.load(modTimes)
+}
+
+// scan lists the template files of the views along with their modification times.
+func (v *Views) scan() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	err := fs.WalkDir(v.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != v.Extension {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		modTimes[p] = info.ModTime()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("views: %w", err)
+	}
+	return modTimes, nil
+}
+
+// load parses the given template files, the shared layouts and partials first, then each page
+// in its own copy of the shared set, so that pages can define the same blocks without conflicting.
+func (v *Views) load(modTimes map[string]time.Time) error {
+	shared := template.New("").Funcs(template.FuncMap{"url": v.url}).Funcs(v.funcs)
+	var pages []string
+	for p := range modTimes {
+		name := strings.TrimSuffix(p, v.Extension)
+		if !strings.HasPrefix(name, "layouts/") && !strings.HasPrefix(name, "partials/") {
+			pages = append(pages, p)
+			continue
+		}
+		if err := v.parse(shared, name, p); err != nil {
+			return err
+		}
+	}
+	if v.Layout != "" && shared.Lookup(v.Layout) == nil {
+		return fmt.Errorf("views: layout %q not found", v.Layout)
+	}
+
+	templates := make(map[string]*template.Template, len(modTimes))
+	for _, p := range pages {
+		name := strings.TrimSuffix(p, v.Extension)
+		t, err := shared.Clone()
+		if err != nil {
+			return fmt.Errorf("views: %w", err)
+		}
+		if err := v.parse(t, name, p); err != nil {
+			return err
+		}
+		if _, err := t.New("content").Parse(fmt.Sprintf("{{template %q .}}", name)); err != nil {
+			return fmt.Errorf("views: %w", err)
+		}
+		templates[name] = t
+	}
+	for _, t := range shared.Templates() {
+		if t.Name() != "" {
+			templates[t.Name()] = shared
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.templates = templates
+	v.modTimes = modTimes
+	return nil
+}
+
+// parse reads the template file at path p and parses it into the set as the template of the given name.
+func (v *Views) parse(set *template.Template, name, p string) error {
+	text, err := fs.ReadFile(v.fsys, p)
+	if err != nil {
+		return fmt.Errorf("views: %w", err)
+	}
+	if _, err := set.New(name).Parse(string(text)); err != nil {
+		return fmt.Errorf("views: %w", err)
+	}
+	return nil
+}
+
+// reloadIfChanged reloads the templates when files were added, removed or modified since they were loaded.
+func (v *Views) reloadIfChanged() error {
+	modTimes, err := v.scan()
+	if err != nil {
+		return err
+	}
+	v.mu.RLock()
+	changed := !maps.EqualFunc(modTimes, v.modTimes, time.Time.Equal)
+	v.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+	return v.load(modTimes)
+}
+
+// ExecuteTemplate renders the named template with the given data to w.
+// Pages are rendered within the Layout when it is set, while layouts and partials are rendered on their own.
+// In development mode, the files are first walked, and the templates reloaded if they changed.
+// It returns an error if no template has this name or if the rendering fails.
+// It implements the context.HTMLRenderer interface used by the HTML method of the context.
+func (v *Views) ExecuteTemplate(w io.Writer, name string, data any) error {
+	if v.reload {
+		if err := v.reloadIfChanged(); err != nil {
+			return err
+		}
+	}
+
+	v.mu.RLock()
+	t, ok := v.templates[name]
+	v.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("views: no template named %q", name)
+	}
+	if v.Layout != "" && t.Lookup("content") != nil {
+		name = v.Layout
+	}
+	return t.ExecuteTemplate(w, name, data)
+}
+
+// url is the url template function, building the URL of the named route with Router.URL
+// from pairs of parameter names and values, such as {{url "user.show" "id" .ID}}.
+func (v *Views) url(name string, pairs ...any) (string, error) {
+	if v.urls == nil {
+		return "", fmt.Errorf("views: cannot build the URL of route %q: views are not attached to a router", name)
+	}
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("views: cannot build the URL of route %q: parameters must be given as name and value pairs", name)
+	}
+	params := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		params[fmt.Sprint(pairs[i])] = fmt.Sprint(pairs[i+1])
+	}
+	return v.urls.URL(name, params)
+}
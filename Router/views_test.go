@@ -0,0 +1,167 @@
+package Router
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	context "github.com/ines-mgg/LetsGoBack/Context"
+)
+
+// viewsFS returns the templates of the views tests, with a layout, a partial using the url function and two pages.
+func viewsFS() fstest.MapFS {
+	modTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return fstest.MapFS{
+		"layouts/base.html": {Data: []byte(`<title>{{block "title" .}}Admin{{end}}</title>{{template "partials/nav" .}}{{template "content" .}}`), ModTime: modTime},
+		"partials/nav.html": {Data: []byte(`<a href="{{url "user.show" "id" 1}}">Profile</a>`), ModTime: modTime},
+		"admin/users.html":  {Data: []byte(`{{define "title"}}Users{{end}}<ul>{{range .}}<li>{{upper .}}</li>{{end}}</ul>`), ModTime: modTime},
+		"users/show.html":   {Data: []byte(`<p>User {{.}}</p>`), ModTime: modTime},
+		"README.md":         {Data: []byte(`{{not a template`), ModTime: modTime},
+	}
+}
+
+// viewsRouter returns a router rendering the views of the given file system with the layout.
+func viewsRouter(fsys fstest.MapFS, devMode bool) *Router {
+	r := NewRouter()
+	r.DevMode = devMode
+	r.Views = NewViewsFS(fsys).Funcs(template.FuncMap{"upper": strings.ToUpper})
+	r.Views.Layout = "layouts/base"
+	r.GET("/users/:id", func(c *context.Context) {
+		c.HTML(http.StatusOK, "users/show", c.Param("id"))
+	}).Name("user.show")
+	r.GET("/admin/users", func(c *context.Context) {
+		c.HTML(http.StatusOK, "admin/users", []string{"ada", "linus"})
+	})
+	r.GET("/nav", func(c *context.Context) {
+		c.HTML(http.StatusOK, "partials/nav", nil)
+	})
+	r.GET("/missing", func(c *context.Context) {
+		c.HTML(http.StatusOK, "users/missing", nil)
+	})
+	return r
+}
+
+// get serves a GET request for the path with the router and returns the response.
+func get(r *Router, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	return w
+}
+
+func TestViews(t *testing.T) {
+	r := viewsRouter(viewsFS(), false)
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		// Pages are wrapped in the layout, which includes the partial, and can override its blocks.
+		{"/users/7", http.StatusOK, `<title>Admin</title><a href="/users/1">Profile</a><p>User 7</p>`},
+		{"/admin/users", http.StatusOK, `<title>Users</title><a href="/users/1">Profile</a><ul><li>ADA</li><li>LINUS</li></ul>`},
+		// Partials are rendered on their own, without the layout.
+		{"/nav", http.StatusOK, `<a href="/users/1">Profile</a>`},
+		{"/missing", http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		w := get(r, tt.path)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d: %s", tt.path, tt.status, w.Code, w.Body)
+		}
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s: expected %s, got %s", tt.path, tt.body, w.Body)
+		}
+		if tt.status == http.StatusOK && w.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+			t.Errorf("%s: expected an HTML response, got %s", tt.path, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestViewsLoadErrors(t *testing.T) {
+	fsys := viewsFS()
+	v := NewViewsFS(fsys).Funcs(template.FuncMap{"upper": strings.ToUpper})
+	v.urls = NewRouter()
+	v.urls.GET("/users/:id", noop).Name("user.show")
+	v.Layout = "layouts/missing"
+	if err := v.Load(); err == nil || !strings.Contains(err.Error(), `layout "layouts/missing" not found`) {
+		t.Errorf("expected a missing layout error, got %v", err)
+	}
+
+	v.Layout = "layouts/base"
+	if err := v.Load(); err != nil {
+		t.Fatal(err)
+	}
+	// A parse error is reported, and the templates already loaded are kept.
+	fsys["users/show.html"] = &fstest.MapFile{Data: []byte(`{{.Name`)}
+	if err := v.Load(); err == nil {
+		t.Error("expected a parse error")
+	}
+	if err := v.ExecuteTemplate(&strings.Builder{}, "admin/users", nil); err != nil {
+		t.Errorf("expected the loaded templates to be kept, got %v", err)
+	}
+	if err := v.ExecuteTemplate(&strings.Builder{}, "users/missing", nil); err == nil || !strings.Contains(err.Error(), `no template named "users/missing"`) {
+		t.Errorf("expected a missing template error, got %v", err)
+	}
+
+	// Views that cannot be loaded make Compile panic.
+	r := viewsRouter(fsys, false)
+	expectPanic(t, "router: cannot load views", r.Compile)
+}
+
+func TestViewsURL(t *testing.T) {
+	r := viewsRouter(viewsFS(), false)
+	r.Compile()
+	tests := []struct {
+		name  string
+		pairs []any
+		url   string
+		err   string
+	}{
+		{"user.show", []any{"id", 42}, "/users/42", ""},
+		{"user.show", []any{"id"}, "", "name and value pairs"},
+		{"user.show", nil, "", "missing"},
+		{"user.unknown", nil, "", "user.unknown"},
+	}
+	for _, tt := range tests {
+		url, err := r.Views.url(tt.name, tt.pairs...)
+		if url != tt.url || (tt.err == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("url %s %v: expected %q and an error containing %q, got %q and %v", tt.name, tt.pairs, tt.url, tt.err, url, err)
+		}
+	}
+
+	if _, err := NewViewsFS(viewsFS()).url("user.show", "id", 1); err == nil {
+		t.Error("expected an error for views not attached to a router")
+	}
+}
+
+func TestViewsReload(t *testing.T) {
+	for _, devMode := range []bool{false, true} {
+		fsys := viewsFS()
+		r := viewsRouter(fsys, devMode)
+		if body := get(r, "/users/7").Body.String(); !strings.HasSuffix(body, "<p>User 7</p>") {
+			t.Fatalf("unexpected body %s", body)
+		}
+
+		// Templates are reloaded in development mode only, when their modification time changes.
+		fsys["users/show.html"] = &fstest.MapFile{Data: []byte(`<p>Member {{.}}</p>`), ModTime: time.Now()}
+		fsys["users/new.html"] = &fstest.MapFile{Data: []byte(`<p>New</p>`), ModTime: time.Now()}
+
+		body := get(r, "/users/7").Body.String()
+		if reloaded := strings.HasSuffix(body, "<p>Member 7</p>"); reloaded != devMode {
+			t.Errorf("dev mode %v: expected reloaded to be %v, got %s", devMode, devMode, body)
+		}
+		err := r.Views.ExecuteTemplate(&strings.Builder{}, "users/new", nil)
+		if (err == nil) != devMode {
+			t.Errorf("dev mode %v: unexpected error for an added template: %v", devMode, err)
+		}
+
+		// A template that no longer parses is reported by the next render, in development mode.
+		fsys["users/show.html"] = &fstest.MapFile{Data: []byte(`{{.Name`), ModTime: time.Now().Add(time.Second)}
+		if w := get(r, "/users/7"); (w.Code == http.StatusInternalServerError) != devMode {
+			t.Errorf("dev mode %v: unexpected status %d for a broken template", devMode, w.Code)
+		}
+	}
+}